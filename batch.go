@@ -0,0 +1,144 @@
+package dblite
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// InsertManyBatch inserts models with multi-row VALUES statements of at most
+// rowsPerBatch rows, or as many rows as the dialect's parameter limit allows
+// when rowsPerBatch <= 0. The On clause is applied to every batch and all
+// batches run in a single transaction. A batch ends early rather than repeat
+// a key of its ON CONFLICT(cols) target, which postgres cannot upsert twice
+// in one statement, so rows are upserted as they would be one by one. On
+// failure the ExecError index is that of the first record of the failing
+// batch.
+func InsertManyBatch[T ITable[T]](db DB, models []T, insertCols []string, on On, rowsPerBatch int) error {
+	return InsertManyBatchContext(context.Background(), db, models, insertCols, on, rowsPerBatch)
+}
+
+func InsertManyBatchContext[T ITable[T]](ctx context.Context, db DB, models []T, insertCols []string, on On, rowsPerBatch int) error {
+	if len(models) == 0 {
+		return nil
+	}
+	if len(insertCols) == 0 {
+		var err error
+		insertCols, err = insertColumns(models[0], false)
+		if err != nil {
+			return err
+		}
+	}
+	insertCols = withUpsertColumns(insertCols, on)
+
+	var cols, _, err = columnValues(models[0], insertCols)
+	if err != nil {
+		return err
+	}
+	if len(cols) == 0 {
+		return fmt.Errorf("no columns to insert into %v", models[0].TableName())
+	}
+
+	var dialect = db.Dialect()
+	_, onArgs, err := manyOnClause(dialect, models[0], on, 0)
+	if err != nil {
+		return err
+	}
+	var maxRows = (dialect.MaxParameters() - len(onArgs)) / len(cols)
+	if maxRows < 1 {
+		return fmt.Errorf("a row of %d columns exceeds the %v parameter limit", len(cols), dialect.Name())
+	}
+	if rowsPerBatch <= 0 || rowsPerBatch > maxRows {
+		rowsPerBatch = maxRows
+	}
+
+	var keys = conflictColumns(on)
+	return WithTx(ctx, db, func(tx *Tx) error {
+		for start, end := 0, 0; start < len(models); start = end {
+			end, err = batchEnd(models, start, rowsPerBatch, keys)
+			if err != nil {
+				return err
+			}
+			query, args, err := insertBatchStatement(dialect, models[start:end], cols, on)
+			if err != nil {
+				return err
+			}
+			if _, err = ExecContext(ctx, tx, query, args...); err != nil {
+				return &ExecError{Index: start, Query: query, Err: err}
+			}
+		}
+		return nil
+	})
+}
+
+var conflictTarget = regexp.MustCompile(`(?i)^\s*CONFLICT\s*\(([^)]*)\)`)
+
+// conflictColumns returns the columns of an ON CONFLICT(cols) target.
+func conflictColumns(on On) []string {
+	var match = conflictTarget.FindStringSubmatch(on.On)
+	if match == nil {
+		return nil
+	}
+	var cols = strings.Split(match[1], ",")
+	for i, col := range cols {
+		cols[i] = strings.Trim(strings.TrimSpace(col), "\"`")
+	}
+	return cols
+}
+
+// batchEnd ends the batch from start after size rows, or before the first
+// row whose conflict key repeats one already in the batch.
+func batchEnd[T ITable[T]](models []T, start, size int, keys []string) (int, error) {
+	var end = min(start+size, len(models))
+	if len(keys) == 0 {
+		return end, nil
+	}
+	var seen = make(map[string]bool, end-start)
+	for i := start; i < end; i++ {
+		var _, refs, err = columnValues(models[i], keys)
+		if err != nil {
+			return 0, err
+		}
+		if len(refs) == 0 { // an expression target, such as lower(email)
+			return end, nil
+		}
+		var key = make([]string, len(refs))
+		for j, ref := range refs {
+			key[j] = fmt.Sprint(reflect.ValueOf(ref).Elem().Interface())
+		}
+		var k = strings.Join(key, "\x00")
+		if seen[k] {
+			return i, nil
+		}
+		seen[k] = true
+	}
+	return end, nil
+}
+
+func insertBatchStatement[T ITable[T]](dialect Dialect, models []T, cols []string, on On) (string, []any, error) {
+	var rows = make([]string, len(models))
+	var args = make([]any, 0, len(models)*len(cols))
+	for i, model := range models {
+		var _, values, err = columnValues(model, cols)
+		if err != nil {
+			return "", nil, err
+		}
+		rows[i] = "(" + placeholders(dialect, len(values), len(args)) + ")"
+		args = append(args, values...)
+	}
+
+	var sqlStatement = fmt.Sprintf(`INSERT INTO %v(%v) VALUES %v`,
+		models[0].TableName(), QuotedColumnNames(cols, dialect), strings.Join(rows, ","))
+
+	if len(on.On) > 0 || len(on.UpsertColumns) > 0 {
+		var sqlOn, onArgs, err = manyOnClause(dialect, models[0], on, len(args))
+		if err != nil {
+			return "", nil, err
+		}
+		sqlStatement += " ON " + sqlOn
+		args = append(args, onArgs...)
+	}
+	return sqlStatement + ";", args, nil
+}
@@ -0,0 +1,137 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+func TestInsertManyBatch(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test InsertManyBatch", func() {
+		g.It("renders multi-row values", func() {
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com"},
+				{Id: 2, Email: "email2@db.com"},
+			}
+			query, args, err := insertBatchStatement(Postgres, models, []string{"id", "email"}, On{
+				On: "CONFLICT(id) DO UPDATE SET name = ?", Arguments: []any{"dup"},
+			})
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`INSERT INTO model("id","email") VALUES ($1,$2),($3,$4) ON CONFLICT(id) DO UPDATE SET name = $5;`)
+			g.Assert(len(args)).Equal(5)
+		})
+
+		g.It("inserts in chunks inside one transaction", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var models = make([]*Model, 2500)
+			for i := range models {
+				models[i] = &Model{Id: int64(i + 1), Email: fmt.Sprintf("email%d@db.com", i+1)}
+			}
+			var ctx = context.Background()
+			var cols = []string{`id`, `email`}
+			g.Assert(InsertManyBatchContext(ctx, dbInstance, models, cols, On{}, 1000)).IsNil()
+
+			num, err := Count(dbInstance, NewModel(-1), `id`, WhereClause{Where: `1=1`})
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(2500))
+
+			var dups = []*Model{
+				{Id: 3000, Email: "email3000@db.com"},
+				{Id: 3001, Email: "email3001@db.com"},
+				{Id: 3002, Email: "email1@db.com"},
+			}
+			err = InsertManyBatchContext(ctx, dbInstance, dups, cols, On{}, 2)
+			g.Assert(errors.Is(err, ErrUniqueViolation)).IsTrue()
+			var execErr *ExecError
+			g.Assert(errors.As(err, &execErr)).IsTrue()
+			g.Assert(execErr.Index).Equal(2)
+
+			num, err = Count(dbInstance, NewModel(-1), `id`, WhereClause{Where: `1=1`})
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(2500))
+
+			g.Assert(InsertManyBatchContext(ctx, dbInstance, dups[:2], cols, On{On: "CONFLICT(id) DO NOTHING"}, 0)).IsNil()
+		})
+
+		g.It("splits batches before repeated conflict keys", func() {
+			g.Assert(conflictColumns(On{On: `CONFLICT(id, "email") DO NOTHING`})).Equal([]string{"id", "email"})
+			g.Assert(len(conflictColumns(On{On: "DUPLICATE KEY UPDATE name = ?"}))).Equal(0)
+
+			var models = []*Model{
+				{Id: 1, Name: "a"}, {Id: 2, Name: "b"}, {Id: 1, Name: "c"}, {Id: 3, Name: "d"},
+			}
+			end, err := batchEnd(models, 0, 10, []string{"id"})
+			g.Assert(err).IsNil()
+			g.Assert(end).Equal(2)
+			end, err = batchEnd(models, 2, 10, []string{"id"})
+			g.Assert(err).IsNil()
+			g.Assert(end).Equal(4)
+			end, err = batchEnd(models, 0, 10, nil)
+			g.Assert(err).IsNil()
+			g.Assert(end).Equal(4)
+
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var on = On{On: "CONFLICT(id)", UpsertColumns: []string{`name`}}
+			for i, m := range models {
+				m.Email = fmt.Sprintf("email%d@db.com", i)
+			}
+			g.Assert(InsertManyBatch(dbInstance, models, []string{`id`, `email`, `name`}, on, 0)).IsNil()
+			results, err := QueryModels(dbInstance, NewModel(-1))
+			g.Assert(err).IsNil()
+			g.Assert(len(results)).Equal(3)
+			g.Assert(results[0].Name).Equal("c") // upserted as InsertMany would
+		})
+
+		g.It("upserts with excluded values", func() {
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "model1"},
+				{Id: 2, Email: "email2@db.com", Name: "model2"},
+			}
+			var cols = []string{`id`, `email`, `name`}
+			var on = On{On: "CONFLICT(id)", UpsertColumns: []string{`name`}}
+			query, args, err := insertBatchStatement(MySQL, models, cols, on)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal("INSERT INTO model(`id`,`email`,`name`) VALUES (?,?,?),(?,?,?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`);")
+			g.Assert(len(args)).Equal(6)
+
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			g.Assert(InsertMany(dbInstance, models, cols, on)).IsNil()
+			models[0].Name, models[1].Name = "model1-upserted", "model2-upserted"
+			g.Assert(InsertMany(dbInstance, models, cols, on)).IsNil()
+			g.Assert(InsertManyBatch(dbInstance, models, cols, on, 0)).IsNil()
+
+			results, err := QueryModels(dbInstance, NewModel(-1))
+			g.Assert(err).IsNil()
+			g.Assert(len(results)).Equal(2)
+			g.Assert(results[0].Name).Equal("model1-upserted")
+			g.Assert(results[1].Name).Equal("model2-upserted")
+
+			// upsert columns missing from the insert columns are still inserted
+			models[0].Name, models[1].Name = "model1-again", "model2-again"
+			g.Assert(InsertManyBatch(dbInstance, models, cols[:2], on, 0)).IsNil()
+			results, err = QueryModels(dbInstance, NewModel(-1))
+			g.Assert(err).IsNil()
+			g.Assert(results[0].Name).Equal("model1-again")
+			g.Assert(results[1].Name).Equal("model2-again")
+			g.Assert(cols).Equal([]string{`id`, `email`, `name`})
+
+			var bad = On{On: "CONFLICT(id)", UpsertColumns: []string{`name`}, Arguments: []any{"x"}}
+			g.Assert(errors.Is(InsertMany(dbInstance, models, cols, bad), errUpsertArguments)).IsTrue()
+			_, _, err = Insert(dbInstance, models[0], cols, bad)
+			g.Assert(errors.Is(err, errUpsertArguments)).IsTrue()
+		})
+	})
+}
@@ -1,12 +1,84 @@
 package dblite
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
 type On struct {
 	On            string
 	UpsertColumns []string
 	Arguments     []any
+	Named         any // map[string]any or struct for :name parameters
 }
 
 type WhereClause struct {
 	Where     string
 	Arguments []any
+	Named     any // map[string]any or struct for :name parameters
+
+	cond Cond
+}
+
+var errNamedAndPositional = errors.New("named and positional arguments cannot be mixed")
+
+var errUpsertArguments = errors.New("dblite: On arguments cannot be combined with UpsertColumns")
+
+// bind renders the clause for dialect with its placeholders numbered after
+// offset, expanding named parameters and rewriting the ? placeholders of
+// hand written clauses.
+func (wc WhereClause) bind(dialect Dialect, offset int) (WhereClause, error) {
+	if wc.cond != nil {
+		return compileAt(dialect, wc.cond, offset), nil
+	}
+	var where, args, err = bindClause(dialect, wc.Where, wc.Arguments, wc.Named, offset)
+	if err != nil {
+		return wc, err
+	}
+	return WhereClause{Where: where, Arguments: args}, nil
+}
+
+// bind renders the raw On clause and its arguments after offset.
+func (on On) bind(dialect Dialect, offset int) (string, []any, error) {
+	if err := on.check(dialect); err != nil {
+		return "", nil, err
+	}
+	return bindClause(dialect, on.On, on.Arguments, on.Named, offset)
+}
+
+// check rejects a raw On clause written in another dialect's upsert syntax:
+// mysql only knows ON DUPLICATE KEY, sqlite and postgres only ON CONFLICT.
+func (on On) check(dialect Dialect) error {
+	var clause = strings.ToUpper(strings.TrimSpace(on.On))
+	for _, other := range dialects {
+		var syntax = other.UpsertSyntax()
+		if syntax != dialect.UpsertSyntax() && strings.HasPrefix(clause, syntax) {
+			return fmt.Errorf("dblite: ON %v is not supported by %v", syntax, dialect.Name())
+		}
+	}
+	return nil
+}
+
+// upsert renders the upsert of assignments on the conflict target of on, which
+// takes no arguments of its own (mysql drops the target altogether).
+func (on On) upsert(dialect Dialect, assignments string) (string, error) {
+	if len(on.Arguments) > 0 || on.Named != nil {
+		return "", errUpsertArguments
+	}
+	return dialect.Upsert(on.On, assignments)
+}
+
+func bindClause(dialect Dialect, query string, args []any, named any, offset int) (string, []any, error) {
+	if named != nil {
+		if len(args) > 0 {
+			return "", nil, errNamedAndPositional
+		}
+		var err error
+		query, args, err = expandNamed(query, named)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return rebind(dialect, query, offset), args, nil
 }
@@ -0,0 +1,148 @@
+package dblite
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Cond interface {
+	render(w *condWriter) string
+}
+
+type condWriter struct {
+	dialect Dialect
+	offset  int
+	args    []any
+}
+
+func (w *condWriter) bind(v any) string {
+	w.args = append(w.args, v)
+	return w.dialect.Placeholder(w.offset + len(w.args))
+}
+
+// Compile renders cond into a WhereClause using the dialect's placeholders.
+func Compile(dialect Dialect, cond Cond) WhereClause {
+	return compileAt(dialect, cond, 0)
+}
+
+func compileAt(dialect Dialect, cond Cond, offset int) WhereClause {
+	var w = &condWriter{dialect: dialect, offset: offset, args: make([]any, 0)}
+	var where = cond.render(w)
+	return WhereClause{Where: where, Arguments: w.args, cond: cond}
+}
+
+type compare struct {
+	col string
+	op  string
+	val any
+}
+
+func (c compare) render(w *condWriter) string {
+	return fmt.Sprintf("%v %v %v", w.dialect.Quote(c.col), c.op, w.bind(c.val))
+}
+
+func Eq(col string, val any) Cond {
+	return compare{col, "=", val}
+}
+
+func Ne(col string, val any) Cond {
+	return compare{col, "<>", val}
+}
+
+func Lt(col string, val any) Cond {
+	return compare{col, "<", val}
+}
+
+func Le(col string, val any) Cond {
+	return compare{col, "<=", val}
+}
+
+func Gt(col string, val any) Cond {
+	return compare{col, ">", val}
+}
+
+func Ge(col string, val any) Cond {
+	return compare{col, ">=", val}
+}
+
+func Like(col string, pattern string) Cond {
+	return compare{col, "LIKE", pattern}
+}
+
+type in struct {
+	col  string
+	vals []any
+}
+
+func (c in) render(w *condWriter) string {
+	if len(c.vals) == 0 {
+		return "1=0"
+	}
+	var holders = MapFn(c.vals, w.bind)
+	return fmt.Sprintf("%v IN (%v)", w.dialect.Quote(c.col), strings.Join(holders, ","))
+}
+
+func In[V any](col string, vals ...V) Cond {
+	return in{col, MapFn(vals, func(v V) any { return v })}
+}
+
+type between struct {
+	col    string
+	lo, hi any
+}
+
+func (c between) render(w *condWriter) string {
+	return fmt.Sprintf("%v BETWEEN %v AND %v", w.dialect.Quote(c.col), w.bind(c.lo), w.bind(c.hi))
+}
+
+func Between(col string, lo, hi any) Cond {
+	return between{col, lo, hi}
+}
+
+type isNull struct {
+	col string
+}
+
+func (c isNull) render(w *condWriter) string {
+	return fmt.Sprintf("%v IS NULL", w.dialect.Quote(c.col))
+}
+
+func IsNull(col string) Cond {
+	return isNull{col}
+}
+
+type junction struct {
+	op    string
+	conds []Cond
+	empty string
+}
+
+func (c junction) render(w *condWriter) string {
+	if len(c.conds) == 0 {
+		return c.empty
+	}
+	var parts = MapFn(c.conds, func(cond Cond) string {
+		return "(" + cond.render(w) + ")"
+	})
+	return strings.Join(parts, " "+c.op+" ")
+}
+
+func And(conds ...Cond) Cond {
+	return junction{"AND", conds, "1=1"}
+}
+
+func Or(conds ...Cond) Cond {
+	return junction{"OR", conds, "1=0"}
+}
+
+type not struct {
+	cond Cond
+}
+
+func (c not) render(w *condWriter) string {
+	return "NOT (" + c.cond.render(w) + ")"
+}
+
+func Not(cond Cond) Cond {
+	return not{cond}
+}
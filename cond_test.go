@@ -0,0 +1,142 @@
+package dblite
+
+import (
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+func TestCond(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test Cond", func() {
+		g.It("compiles with dialect placeholders", func() {
+			var cond = And(
+				Eq("name", "model1"),
+				Or(In("id", 1, 2, 3), Between("active", 0, 1)),
+				Not(IsNull("email")),
+				Like("address", "%street"),
+			)
+			var wc = Compile(Postgres, cond)
+			g.Assert(wc.Where).Equal(`("name" = $1) AND (("id" IN ($2,$3,$4)) OR ("active" BETWEEN $5 AND $6)) AND (NOT ("email" IS NULL)) AND ("address" LIKE $7)`)
+			g.Assert(wc.Arguments).Equal([]any{"model1", 1, 2, 3, 0, 1, "%street"})
+
+			wc = Compile(SQLite3, Or(Eq("id", 1), In[int]("id")))
+			g.Assert(wc.Where).Equal(`("id" = ?) OR (1=0)`)
+		})
+
+		g.It("numbers update conditions after the set columns", func() {
+			var m = &Model{Id: 7, Name: "model", Address: "123 db street"}
+			query, values, err := updateStatement(Postgres, m, []string{"name", "address"}, Compile(Postgres, Eq("id", m.Id)), nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`UPDATE model SET "name"=$1,"address"=$2 WHERE "id" = $3;`)
+			g.Assert(len(values)).Equal(3)
+		})
+
+		g.It("filters crud helpers", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "model1"},
+				{Id: 2, Email: "email2@db.com", Name: "model2"},
+				{Id: 3, Email: "email3@db.com", Name: "model3"},
+			}
+			g.Assert(InsertMany(dbInstance, models, []string{`id`, `email`, `name`}, On{})).IsNil()
+
+			var d = dbInstance.Dialect()
+			num, err := Count(dbInstance, NewModel(-1), `id`, Compile(d, In("id", 1, 3)))
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(2))
+
+			results, err := QueryModels(dbInstance, NewModel(-1), Compile(d, Not(Eq("name", "model2"))))
+			g.Assert(err).IsNil()
+			g.Assert(len(results)).Equal(2)
+
+			models[0].Name = "model1-updated"
+			bln, err := Update(dbInstance, models[0], []string{`name`}, Compile(d, Eq("id", 1)))
+			g.Assert(err).IsNil()
+			g.Assert(bln).IsTrue()
+
+			n, err := Delete(dbInstance, NewModel(-1), Compile(d, Gt("id", 1)))
+			g.Assert(err).IsNil()
+			g.Assert(n).Equal(int64(2))
+		})
+
+		g.It("expands named parameters", func() {
+			var wc = WhereClause{
+				Where: "name = :name AND id IN (:ids) AND created::date = :day AND note <> ':skip'",
+				Named: map[string]any{"name": "model1", "ids": []int64{1, 2}, "day": "2024-01-01"},
+			}
+			bound, err := wc.bind(Postgres, 0)
+			g.Assert(err).IsNil()
+			g.Assert(bound.Where).Equal("name = $1 AND id IN ($2,$3) AND created::date = $4 AND note <> ':skip'")
+			g.Assert(bound.Arguments).Equal([]any{"model1", int64(1), int64(2), "2024-01-01"})
+
+			bound, err = WhereClause{Where: "email = :email", Named: Model{Email: "email@db.com"}}.bind(SQLite3, 0)
+			g.Assert(err).IsNil()
+			g.Assert(bound.Where).Equal("email = ?")
+			g.Assert(bound.Arguments).Equal([]any{"email@db.com"})
+
+			_, err = WhereClause{Where: "id = :missing", Named: map[string]any{}}.bind(SQLite3, 0)
+			g.Assert(err == nil).IsFalse()
+
+			_, err = WhereClause{Where: "id = :id", Arguments: []any{1}, Named: map[string]any{"id": 1}}.bind(SQLite3, 0)
+			g.Assert(err).Equal(errNamedAndPositional)
+
+			bound, err = WhereClause{
+				Where: "tags[1:2] = :tags AND tags[:2] <> tags[2:] AND id = :id::int",
+				Named: map[string]any{"tags": "a", "id": 1},
+			}.bind(Postgres, 0)
+			g.Assert(err).IsNil()
+			g.Assert(bound.Where).Equal("tags[1:2] = $1 AND tags[:2] <> tags[2:] AND id = $2::int")
+			g.Assert(bound.Arguments).Equal([]any{"a", 1})
+		})
+
+		g.It("leaves positional clauses as written", func() {
+			bound, err := WhereClause{Where: "data ?? 'key' AND id = ?", Arguments: []any{1}}.bind(SQLite3, 0)
+			g.Assert(err).IsNil()
+			g.Assert(bound.Where).Equal("data ?? 'key' AND id = ?")
+
+			// argument counts are left for the driver to check
+			bound, err = WhereClause{Where: "id = ? AND name = ?", Arguments: []any{1}}.bind(Postgres, 0)
+			g.Assert(err).IsNil()
+			g.Assert(bound.Where).Equal("id = $1 AND name = $2")
+
+			bound, err = WhereClause{Where: "ts > '10:30' AND id = :id"}.bind(Postgres, 0)
+			g.Assert(err).IsNil()
+			g.Assert(bound.Where).Equal("ts > '10:30' AND id = :id")
+		})
+
+		g.It("filters crud helpers with named parameters", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "model1"},
+				{Id: 2, Email: "email2@db.com", Name: "model2"},
+				{Id: 3, Email: "email3@db.com", Name: "model1"},
+			}
+			g.Assert(InsertMany(dbInstance, models, []string{`id`, `email`, `name`}, On{})).IsNil()
+
+			num, err := Count(dbInstance, NewModel(-1), `id`, WhereClause{
+				Where: "name = :name AND id IN (:ids)",
+				Named: map[string]any{"name": "model1", "ids": []int{1, 2, 3}},
+			})
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(2))
+
+			models[0].Address = "new address"
+			_, _, err = Insert(dbInstance, models[0], []string{`id`, `email`}, On{
+				On:    "CONFLICT(id) DO UPDATE SET address = :address",
+				Named: models[0],
+			})
+			g.Assert(err).IsNil()
+
+			m, err := First(dbInstance, NewModel(-1), WhereClause{Where: "id = :id", Named: map[string]any{"id": 1}})
+			g.Assert(err).IsNil()
+			g.Assert(m.Address).Equal("new address")
+		})
+	})
+}
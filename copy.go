@@ -0,0 +1,86 @@
+package dblite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+func CopyMany[T ITable[T]](ctx context.Context, db DB, models []T, cols []string) (int64, error) {
+	return CopySeq(ctx, db, slices.Values(models), cols)
+}
+
+// CopySeq bulk loads models with COPY FROM STDIN on postgres, and with a
+// prepared INSERT executed per model on other dialects, in one transaction.
+// Columns are derived from the first model when cols is empty. The sequence
+// is consumed once, so the transaction is never retried. Postgres reports
+// COPY errors for the whole stream, so their ExecError index is -1.
+func CopySeq[T ITable[T]](ctx context.Context, db DB, models iter.Seq[T], cols []string) (int64, error) {
+	var dialect = db.Dialect()
+	var _, copying = dialect.CopyIn("", nil)
+	var count int64
+	var err = withTxPolicy(ctx, db, NoRetry(), func(tx *Tx) error {
+		var stmt *sql.Stmt
+		var query string
+		defer func() {
+			if stmt != nil {
+				stmt.Close()
+			}
+		}()
+
+		for model := range models {
+			if stmt == nil { // columns and statement follow the first model
+				var err error
+				if len(cols) == 0 {
+					cols, err = insertColumns(model, false)
+					if err != nil {
+						return err
+					}
+				}
+				cols, _, err = columnValues(model, cols)
+				if err != nil {
+					return err
+				}
+				query = copyStatement(dialect, model.TableName(), cols)
+				stmt, err = tx.PrepareContext(ctx, query)
+				if err != nil {
+					return translateError(dialect, err)
+				}
+			}
+
+			var _, values, err = columnValues(model, cols)
+			if err != nil {
+				return err
+			}
+			if _, err = stmt.ExecContext(ctx, values...); err != nil {
+				var index = int(count)
+				if copying { // COPY reports errors for the stream, not per row
+					index = -1
+				}
+				return &ExecError{Index: index, Query: query, Err: translateError(dialect, err)}
+			}
+			count++
+		}
+
+		if stmt != nil && copying {
+			if _, err := stmt.ExecContext(ctx); err != nil { // flush the copy buffer
+				return &ExecError{Index: -1, Query: query, Err: translateError(dialect, err)}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func copyStatement(dialect Dialect, table string, cols []string) string {
+	if query, ok := dialect.CopyIn(table, cols); ok {
+		return query
+	}
+	return fmt.Sprintf(`INSERT INTO %v(%v) VALUES (%v);`,
+		table, QuotedColumnNames(cols, dialect), ColumnPlaceholders(cols, dialect))
+}
@@ -0,0 +1,88 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+func TestCopy(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test CopyMany", func() {
+		g.It("renders copy statements", func() {
+			g.Assert(copyStatement(Postgres, "model", []string{"id", "email"})).Equal(`COPY "model" ("id", "email") FROM STDIN`)
+			g.Assert(copyStatement(Postgres, "public.model", []string{"id"})).Equal(`COPY "public"."model" ("id") FROM STDIN`)
+			g.Assert(copyStatement(SQLite3, "model", []string{"id", "email"})).Equal(`INSERT INTO model("id","email") VALUES (?,?);`)
+		})
+
+		g.It("falls back to prepared inserts on sqlite", func() {
+			g.Timeout(1 * time.Hour)
+			initAccountDB()
+			defer deInitDB()
+
+			var ctx = context.Background()
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "model1"},
+				{Id: 2, Email: "email2@db.com", Name: "model2"},
+			}
+			n, err := CopyMany(ctx, dbInstance, models, []string{`id`, `email`, `name`})
+			g.Assert(err).IsNil()
+			g.Assert(n).Equal(int64(2))
+
+			var accounts = func(yield func(*Account) bool) {
+				for i := 0; i < 100; i++ {
+					if !yield(&Account{Email: fmt.Sprintf("email%d@db.com", i)}) {
+						return
+					}
+				}
+			}
+			n, err = CopySeq(ctx, dbInstance, accounts, nil)
+			g.Assert(err).IsNil()
+			g.Assert(n).Equal(int64(100))
+
+			num, err := Count(dbInstance, &Account{}, `id`, WhereClause{Where: `1=1`})
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(100))
+		})
+	})
+}
+
+func TestCopyPostgres(t *testing.T) {
+	var db, err = NewDatabaseSource("postgres", postgresURI)
+	if err != nil {
+		t.Skipf("postgres is not available: %v", err)
+	}
+	defer db.Close()
+
+	g := goblin.Goblin(t)
+	g.Describe("Test CopyMany on postgres", func() {
+		g.It("copies rows and reports errors at the flush", func() {
+			g.Timeout(1 * time.Hour)
+			var ctx = context.Background()
+			_, err := db.Exec(sqlOModel)
+			g.Assert(err).IsNil()
+
+			var models = []*OModel{
+				{Id: 1, Email: "omodel1@odb.com", Name: "omodel1"},
+				{Id: 2, Email: "omodel2@odb.com", Name: "omodel2"},
+			}
+			n, err := CopyMany(ctx, db, models, []string{`id`, `email`, `name`})
+			g.Assert(err).IsNil()
+			g.Assert(n).Equal(int64(2))
+
+			var dups = []*OModel{{Id: 3, Email: "omodel3@odb.com"}, {Id: 1, Email: "omodel4@odb.com"}}
+			_, err = CopyMany(ctx, db, dups, []string{`id`, `email`})
+			g.Assert(errors.Is(err, ErrUniqueViolation)).IsTrue()
+			var execErr *ExecError
+			g.Assert(errors.As(err, &execErr)).IsTrue()
+			g.Assert(execErr.Index).Equal(-1)
+
+			num, err := Count(db, &OModel{}, `id`, WhereClause{Where: `1=1`})
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(2))
+		})
+	})
+}
@@ -1,14 +1,22 @@
 package dblite
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
 )
 
-func Count[T ITable[T]](conn *sql.DB, model T, refCol string, wc WhereClause) (int64, error) {
+func Count[T ITable[T]](db DB, model T, refCol string, wc WhereClause) (int64, error) {
+	return CountContext(context.Background(), db, model, refCol, wc)
+}
+
+func CountContext[T ITable[T]](ctx context.Context, db DB, model T, refCol string, wc WhereClause) (int64, error) {
 	var count int64
+	wc, err := wc.bind(db.Dialect(), 0)
+	if err != nil {
+		return count, err
+	}
 	var query = fmt.Sprintf(`SELECT COUNT(%v) FROM %v WHERE %v;`, refCol, model.TableName(), wc.Where)
-	var rows, err = Query(conn, query, wc.Arguments...)
+	rows, err := QueryContext(ctx, db, query, wc.Arguments...)
 	if err != nil {
 		return count, err
 	}
@@ -1,13 +1,21 @@
 package dblite
 
 import (
+	"context"
 	"database/sql"
 	_ "github.com/mattn/go-sqlite3"
 )
 
+type DB interface {
+	Executor
+	Dialect() Dialect
+}
+
 type Database struct {
-	file string
-	Conn *sql.DB
+	file    string
+	dialect Dialect
+	Conn    *sql.DB
+	Retry   RetryPolicy
 }
 
 func NewDatabase(dbpath string) (*Database, error) {
@@ -15,7 +23,14 @@ func NewDatabase(dbpath string) (*Database, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Database{file: dbpath, Conn: conn}, nil
+	return &Database{file: dbpath, dialect: SQLite3, Conn: conn, Retry: DefaultRetryPolicy()}, nil
+}
+
+func (db *Database) Dialect() Dialect {
+	if db.dialect == nil {
+		return SQLite3
+	}
+	return db.dialect
 }
 
 func (db *Database) Close() {
@@ -25,13 +40,67 @@ func (db *Database) Close() {
 }
 
 func (db *Database) Exec(query string, args ...any) (sql.Result, error) {
-	return Exec(db.Conn, query, args...)
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+func (db *Database) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var res, err = db.Conn.ExecContext(ctx, query, args...)
+	return res, translateError(db.Dialect(), err)
 }
 
-func (db *Database) ExecMany(query string, records [][]any) (error, error) {
-	return ExecMany(db.Conn, query, records)
+func (db *Database) ExecMany(query string, records [][]any) error {
+	return ExecMany(db, query, records)
+}
+
+func (db *Database) ExecManyContext(ctx context.Context, query string, records [][]any) error {
+	return ExecManyContext(ctx, db, query, records)
 }
 
 func (db *Database) Query(query string, args ...any) (*sql.Rows, error) {
-	return db.Conn.Query(query, args...)
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+func (db *Database) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	var rows, err = db.Conn.QueryContext(ctx, query, args...)
+	return rows, translateError(db.Dialect(), err)
+}
+
+func (db *Database) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.Conn.QueryRowContext(ctx, query, args...)
+}
+
+func (db *Database) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return db.Conn.PrepareContext(ctx, query)
+}
+
+func (db *Database) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	var tx, err = db.Conn.BeginTx(ctx, opts)
+	return tx, translateError(db.Dialect(), err)
+}
+
+// WithTx runs fn in a transaction, running it again under db.Retry when the
+// database is busy, so fn must be safe to run more than once. Set Retry to
+// NoRetry() to run it at most once.
+func (db *Database) WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	return withTx(ctx, db.Conn, db.Dialect(), db.Retry, fn)
+}
+
+func (db *Database) Tables(ctx context.Context) ([]string, error) {
+	return Tables(ctx, db)
+}
+
+func (db *Database) Columns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	return Columns(ctx, db, table)
+}
+
+func (db *Database) Indexes(ctx context.Context, table string) ([]IndexInfo, error) {
+	return Indexes(ctx, db, table)
+}
+
+func (db *Database) ForeignKeys(ctx context.Context, table string) ([]ForeignKeyInfo, error) {
+	return ForeignKeys(ctx, db, table)
+}
+
+func (db *Database) retryPolicy() RetryPolicy {
+	return db.Retry
 }
@@ -1,6 +1,9 @@
 package dblite
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/franela/goblin"
 	"os"
@@ -47,28 +50,28 @@ func (model *Model) TableName() string {
 }
 
 func (model *Model) InsertWithArgs() (bool, int64, error) {
-	return Insert(dbInstance.Conn, model, []string{
+	return Insert(dbInstance, model, []string{
 		`id`, `email`, `name`, `address`,
 	}, On{
 		On:        "CONFLICT(id) DO UPDATE SET email=?, name=?, address=?",
 		Arguments: []any{model.Email, model.Name, model.Address},
-	}, "sqlite3")
+	})
 }
 
 func (model *Model) InsertOnConflictDoNothing() (bool, int64, error) {
-	return Insert(dbInstance.Conn, model, []string{
+	return Insert(dbInstance, model, []string{
 		`id`, `email`, `name`, `address`,
 	}, On{
 		On: "CONFLICT(id) DO NOTHING",
-	}, "sqlite3")
+	})
 }
 
 func (model *Model) Upsert() (bool, int64, error) {
 	var columns = []string{`id`, `email`, `name`, `address`}
-	return Insert(dbInstance.Conn, model, columns, On{
+	return Insert(dbInstance, model, columns, On{
 		On:            "CONFLICT(id)",
 		UpsertColumns: columns[1:],
-	}, "sqlite3")
+	})
 }
 
 func initDB() {
@@ -129,12 +132,12 @@ func TestDBLite(t *testing.T) {
 				g.Assert(bln).IsTrue()
 				g.Assert(err).IsNil()
 			}
-			num, err := Count(dbInstance.Conn, NewModel(-1), `id`, WhereClause{
+			num, err := Count(dbInstance, NewModel(-1), `id`, WhereClause{
 				Where: `name=?`, Arguments: []any{"model1"},
 			})
 			g.Assert(err).IsNil()
 			g.Assert(num).Equal(int64(3))
-			num, err = Count(dbInstance.Conn, NewModel(-1), `id`, WhereClause{
+			num, err = Count(dbInstance, NewModel(-1), `id`, WhereClause{
 				Where: `name=?`, Arguments: []any{"model4"},
 			})
 			g.Assert(err).IsNil()
@@ -202,11 +205,203 @@ func TestDBLite(t *testing.T) {
 			w := WhereClause{
 				Where: `name=?`, Arguments: []any{"model100"},
 			}
-			n, err := Delete(dbInstance.Conn, m, w)
+			n, err := Delete(dbInstance, m, w)
 			g.Assert(n).Equal(int64(1))
 			g.Assert(err).IsNil()
 		})
 
+		g.It("model insert many with cancelled context", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "model1", Address: "123 db street"},
+				{Id: 2, Email: "email2@db.com", Name: "model2", Address: "124 db street"},
+			}
+			var ctx, cancel = context.WithCancel(context.Background())
+			cancel()
+
+			err := InsertManyContext(ctx, dbInstance, models, []string{`id`, `email`, `name`}, On{})
+			g.Assert(errors.Is(err, context.Canceled)).IsTrue()
+
+			num, err := Count(dbInstance, NewModel(-1), `id`, WhereClause{Where: `1=1`})
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(0))
+		})
+
+		g.It("model insert many reports the failing record", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "model1"},
+				{Id: 2, Email: "email2@db.com", Name: "model2"},
+				{Id: 3, Email: "email1@db.com", Name: "model3"},
+			}
+			err := InsertMany(dbInstance, models, []string{`id`, `email`, `name`}, On{})
+			g.Assert(errors.Is(err, ErrUniqueViolation)).IsTrue()
+
+			var execErr *ExecError
+			g.Assert(errors.As(err, &execErr)).IsTrue()
+			g.Assert(execErr.Index).Equal(2)
+
+			num, err := Count(dbInstance, NewModel(-1), `id`, WhereClause{Where: `1=1`})
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(0))
+
+			_, _, err = models[0].InsertOnConflictDoNothing()
+			g.Assert(err).IsNil()
+			_, _, err = Insert(dbInstance, models[2], []string{`id`, `email`}, On{})
+			g.Assert(errors.Is(err, ErrUniqueViolation)).IsTrue()
+		})
+
+		g.It("model first and get one", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "model1"},
+				{Id: 2, Email: "email2@db.com", Name: "model1"},
+				{Id: 3, Email: "email3@db.com", Name: "model3"},
+			}
+			g.Assert(InsertMany(dbInstance, models, []string{`id`, `email`, `name`}, On{})).IsNil()
+
+			m, err := First(dbInstance, NewModel(-1), WhereClause{Where: `name=?`, Arguments: []any{"model3"}})
+			g.Assert(err).IsNil()
+			g.Assert(m.Id).Equal(int64(3))
+
+			_, err = First(dbInstance, NewModel(-1), WhereClause{Where: `name=?`, Arguments: []any{"none"}})
+			g.Assert(errors.Is(err, ErrNotFound)).IsTrue()
+			g.Assert(errors.Is(err, sql.ErrNoRows)).IsTrue()
+
+			m, err = GetOne(dbInstance, NewModel(-1), WhereClause{Where: `email=?`, Arguments: []any{"email2@db.com"}})
+			g.Assert(err).IsNil()
+			g.Assert(m.Id).Equal(int64(2))
+
+			_, err = GetOne(dbInstance, NewModel(-1), WhereClause{Where: `name=?`, Arguments: []any{"model1"}})
+			g.Assert(errors.Is(err, ErrMultipleRows)).IsTrue()
+		})
+
+		g.It("model writes inside a transaction", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var ctx = context.Background()
+			var cols = []string{`id`, `email`, `name`}
+			err := dbInstance.WithTx(ctx, func(tx *Tx) error {
+				var m = &Model{Id: 1, Email: "email1@db.com", Name: "model1"}
+				if _, _, err := InsertContext(ctx, tx, m, cols, On{}); err != nil {
+					return err
+				}
+				m.Name = "model1-updated"
+				_, err := UpdateContext(ctx, tx, m, []string{`name`}, WhereClause{
+					Where: `id=?`, Arguments: []any{m.Id},
+				})
+				return err
+			})
+			g.Assert(err).IsNil()
+
+			m, err := QueryModel(dbInstance, NewModel(-1), WhereClause{Where: `id=?`, Arguments: []any{1}})
+			g.Assert(err).IsNil()
+			g.Assert(m.Name).Equal("model1-updated")
+
+			var errAbort = errors.New("abort")
+			err = dbInstance.WithTx(ctx, func(tx *Tx) error {
+				var m = &Model{Id: 2, Email: "email2@db.com", Name: "model2"}
+				if _, _, err := InsertContext(ctx, tx, m, cols, On{}); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			g.Assert(errors.Is(err, errAbort)).IsTrue()
+
+			func() {
+				defer func() { g.Assert(recover() != nil).IsTrue() }()
+				_ = dbInstance.WithTx(ctx, func(tx *Tx) error {
+					var m = &Model{Id: 3, Email: "email3@db.com", Name: "model3"}
+					_, _, _ = InsertContext(ctx, tx, m, cols, On{})
+					panic("boom")
+				})
+			}()
+
+			num, err := Count(dbInstance, NewModel(-1), `id`, WhereClause{Where: `1=1`})
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(1))
+		})
+
+		g.It("model nested transactions", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var ctx = context.Background()
+			var cols = []string{`id`, `email`, `name`}
+			var insert = func(db DB, id int64) error {
+				return WithTx(ctx, db, func(tx *Tx) error {
+					var m = &Model{Id: id, Email: fmt.Sprintf("email%v@db.com", id)}
+					_, _, err := InsertContext(ctx, tx, m, cols, On{})
+					return err
+				})
+			}
+
+			err := dbInstance.WithTx(ctx, func(tx *Tx) error {
+				if err := insert(tx, 1); err != nil {
+					return err
+				}
+				// duplicate id fails and only its savepoint is rolled back
+				g.Assert(insert(tx, 1) == nil).IsFalse()
+
+				return tx.WithTx(ctx, func(inner *Tx) error {
+					return insert(inner, 2)
+				})
+			})
+			g.Assert(err).IsNil()
+
+			num, err := Count(dbInstance, NewModel(-1), `id`, WhereClause{Where: `1=1`})
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(2))
+		})
+
+		g.It("model query iterator", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "model1"},
+				{Id: 2, Email: "email2@db.com", Name: "model1"},
+				{Id: 3, Email: "email3@db.com", Name: "model3"},
+			}
+			g.Assert(InsertMany(dbInstance, models, []string{`id`, `email`, `name`}, On{})).IsNil()
+
+			var ctx = context.Background()
+			var ids []int64
+			for m, err := range QueryIter(ctx, dbInstance, NewModel(-1), WhereClause{Where: `name=?`, Arguments: []any{"model1"}}) {
+				g.Assert(err).IsNil()
+				ids = append(ids, m.Id)
+			}
+			g.Assert(ids).Equal([]int64{1, 2})
+
+			// breaking early releases the connection
+			for m, err := range Select(NewModel(-1)).OrderBy("id").Iter(ctx, dbInstance) {
+				g.Assert(err).IsNil()
+				g.Assert(m.Id).Equal(int64(1))
+				break
+			}
+			g.Assert(dbInstance.Conn.Stats().InUse).Equal(0)
+
+			var count = 0
+			for _, err := range QueryIter(ctx, dbInstance, NewModel(-1), WhereClause{Where: `name=?`}) {
+				g.Assert(err == nil).IsFalse()
+				count++
+			}
+			g.Assert(count).Equal(1)
+		})
+
 	})
 
 }
@@ -1,15 +1,21 @@
 package dblite
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
 )
 
-func Delete[T ITable[T]](conn *sql.DB, model T, wc WhereClause) (int64, error) {
-	var query = fmt.Sprintf(
-		`DELETE FROM %v WHERE %v;`, model.TableName(), wc.Where)
+func Delete[T ITable[T]](db DB, model T, wc WhereClause) (int64, error) {
+	return DeleteContext(context.Background(), db, model, wc)
+}
+
+func DeleteContext[T ITable[T]](ctx context.Context, db DB, model T, wc WhereClause) (int64, error) {
+	query, args, err := deleteStatement(db.Dialect(), model, wc, nil)
+	if err != nil {
+		return 0, err
+	}
 
-	var res, err = Exec(conn, query, wc.Arguments...)
+	res, err := ExecContext(ctx, db, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -19,3 +25,36 @@ func Delete[T ITable[T]](conn *sql.DB, model T, wc WhereClause) (int64, error) {
 	}
 	return count, nil
 }
+
+// DeleteReturning deletes the matching rows and returns a new model per row
+// holding only the returning columns.
+func DeleteReturning[T ITable[T]](db DB, model T, wc WhereClause, returning []string) ([]T, error) {
+	return DeleteReturningContext(context.Background(), db, model, wc, returning)
+}
+
+func DeleteReturningContext[T ITable[T]](ctx context.Context, db DB, model T, wc WhereClause, returning []string) ([]T, error) {
+	var dialect = db.Dialect()
+	if err := checkReturning(dialect); err != nil {
+		return nil, err
+	}
+	query, args, err := deleteStatement(dialect, model, wc, returning)
+	if err != nil {
+		return nil, err
+	}
+	var row = model.New()
+	_, refs, err := FieldReferences(returning, row)
+	if err != nil {
+		return nil, err
+	}
+	return queryModels(ctx, db, row, refs, query, args...)
+}
+
+func deleteStatement[T ITable[T]](dialect Dialect, model T, wc WhereClause, returning []string) (string, []any, error) {
+	wc, err := wc.bind(dialect, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	var query = fmt.Sprintf(
+		`DELETE FROM %v WHERE %v%v;`, model.TableName(), wc.Where, returningClause(dialect, returning))
+	return query, wc.Arguments, nil
+}
@@ -0,0 +1,475 @@
+package dblite
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+type Dialect interface {
+	Name() string
+	Placeholder(index int) string
+	Quote(ident string) string
+	Upsert(conflict string, assignments string) (string, error)
+	Excluded(col string) string
+	SupportsReturning() bool
+	SupportsLastInsertId() bool
+	MaxParameters() int
+	ColumnType(field FieldInfo) string
+	AutoIncrement() string
+	SupportsCreateIndexIfNotExists() bool
+	UpsertSyntax() string
+	Inserted(affected int64) bool
+	NoLimit() string
+	CopyIn(table string, cols []string) (string, bool)
+	LockTable(table string) (lock string, unlock string)
+	ForUpdate() string
+	IsRetryable(err error) bool
+	ClassifyError(err error) error
+}
+
+var (
+	SQLite3  Dialect = sqlite3Dialect{}
+	Postgres Dialect = postgresDialect{}
+	MySQL    Dialect = mysqlDialect{}
+)
+
+var dialects = map[string]Dialect{
+	SQLite3.Name():  SQLite3,
+	Postgres.Name(): Postgres,
+	MySQL.Name():    MySQL,
+}
+
+func LookupDialect(name string) (Dialect, error) {
+	if d, ok := dialects[name]; ok {
+		return d, nil
+	}
+	return nil, fmt.Errorf("unknown dialect %q", name)
+}
+
+type sqlite3Dialect struct{}
+
+func (sqlite3Dialect) Name() string {
+	return "sqlite3"
+}
+
+func (sqlite3Dialect) Placeholder(int) string {
+	return "?"
+}
+
+func (sqlite3Dialect) Quote(ident string) string {
+	return quoteIdent(ident, `"`)
+}
+
+func (d sqlite3Dialect) Upsert(conflict string, assignments string) (string, error) {
+	return conflictUpsert(d, conflict, assignments)
+}
+
+func (d sqlite3Dialect) Excluded(col string) string {
+	return "excluded." + d.Quote(col)
+}
+
+// SupportsReturning reports whether the linked sqlite is 3.35 or newer.
+func (sqlite3Dialect) SupportsReturning() bool {
+	var _, versionNumber, _ = sqlite3.Version()
+	return versionNumber >= 3035000
+}
+
+func (sqlite3Dialect) SupportsLastInsertId() bool {
+	return true
+}
+
+// MaxParameters is SQLITE_MAX_VARIABLE_NUMBER, raised from 999 in 3.32.
+func (sqlite3Dialect) MaxParameters() int {
+	var _, versionNumber, _ = sqlite3.Version()
+	if versionNumber >= 3032000 {
+		return 32766
+	}
+	return 999
+}
+
+func (sqlite3Dialect) ColumnType(field FieldInfo) string {
+	var t = storedType(field.Type)
+	switch {
+	case t == timeType:
+		return "TIMESTAMP"
+	case t == bytesType:
+		return "BLOB"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "INTEGER"
+	case reflect.Float32, reflect.Float64:
+		return "REAL"
+	case reflect.String:
+		return "TEXT"
+	}
+	return ""
+}
+
+// AutoIncrement is only valid on an INTEGER PRIMARY KEY column.
+func (sqlite3Dialect) AutoIncrement() string {
+	return "AUTOINCREMENT"
+}
+
+func (sqlite3Dialect) SupportsCreateIndexIfNotExists() bool {
+	return true
+}
+
+func (sqlite3Dialect) UpsertSyntax() string {
+	return "CONFLICT"
+}
+
+func (sqlite3Dialect) Inserted(affected int64) bool {
+	return affected == 1
+}
+
+// NoLimit is the LIMIT that lets an OFFSET follow, which sqlite requires.
+func (sqlite3Dialect) NoLimit() string {
+	return "LIMIT -1"
+}
+
+func (sqlite3Dialect) CopyIn(string, []string) (string, bool) {
+	return "", false
+}
+
+// LockTable takes the database write lock up front with a no-op write, as
+// BEGIN IMMEDIATE would; it is held until the transaction ends.
+func (sqlite3Dialect) LockTable(table string) (string, string) {
+	return fmt.Sprintf(`DELETE FROM %v WHERE 0;`, table), ""
+}
+
+func (sqlite3Dialect) ForUpdate() string {
+	return ""
+}
+
+func (sqlite3Dialect) IsRetryable(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	return false
+}
+
+func (sqlite3Dialect) ClassifyError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return nil
+	}
+	switch sqliteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return ErrUniqueViolation
+	case sqlite3.ErrConstraintForeignKey:
+		return ErrForeignKeyViolation
+	case sqlite3.ErrConstraintNotNull:
+		return ErrNotNullViolation
+	case sqlite3.ErrConstraintCheck:
+		return ErrCheckViolation
+	}
+	return nil
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (postgresDialect) Placeholder(index int) string {
+	return fmt.Sprintf("$%d", index)
+}
+
+// Quote leaves mixed case identifiers such as userId unquoted, so postgres
+// folds them to lower case as it does in hand written SQL; quoting them would
+// make them case sensitive.
+func (postgresDialect) Quote(ident string) string {
+	var parts = strings.Split(ident, ".")
+	for i, part := range parts {
+		if !simpleIdent.MatchString(part) || part == strings.ToLower(part) {
+			parts[i] = quoteIdent(part, `"`)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+var simpleIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (d postgresDialect) Upsert(conflict string, assignments string) (string, error) {
+	return conflictUpsert(d, conflict, assignments)
+}
+
+func (d postgresDialect) Excluded(col string) string {
+	return "excluded." + d.Quote(col)
+}
+
+func (postgresDialect) SupportsReturning() bool {
+	return true
+}
+
+func (postgresDialect) SupportsLastInsertId() bool {
+	return false
+}
+
+func (postgresDialect) MaxParameters() int {
+	return 65535
+}
+
+func (postgresDialect) ColumnType(field FieldInfo) string {
+	var t = storedType(field.Type)
+	switch {
+	case t == timeType:
+		return "TIMESTAMP"
+	case t == bytesType:
+		return "BYTEA"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN"
+	case reflect.Int8, reflect.Int16, reflect.Uint8:
+		return "SMALLINT"
+	case reflect.Int32, reflect.Uint16:
+		return "INTEGER"
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "BIGINT"
+	case reflect.Float32:
+		return "REAL"
+	case reflect.Float64:
+		return "DOUBLE PRECISION"
+	case reflect.String:
+		return "TEXT"
+	}
+	return ""
+}
+
+func (postgresDialect) AutoIncrement() string {
+	return "GENERATED BY DEFAULT AS IDENTITY"
+}
+
+func (postgresDialect) SupportsCreateIndexIfNotExists() bool {
+	return true
+}
+
+func (postgresDialect) UpsertSyntax() string {
+	return "CONFLICT"
+}
+
+func (postgresDialect) Inserted(affected int64) bool {
+	return affected == 1
+}
+
+func (postgresDialect) NoLimit() string {
+	return ""
+}
+
+// CopyIn renders the COPY FROM STDIN statement prepared for pq bulk loads.
+func (postgresDialect) CopyIn(table string, cols []string) (string, bool) {
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		return pq.CopyInSchema(schema, name, cols...), true
+	}
+	return pq.CopyIn(table, cols...), true
+}
+
+// LockTable takes an advisory lock keyed by the table name, released when
+// the transaction ends.
+func (postgresDialect) LockTable(table string) (string, string) {
+	return fmt.Sprintf(`SELECT pg_advisory_xact_lock(hashtext(%v));`, pq.QuoteLiteral(table)), ""
+}
+
+func (postgresDialect) ForUpdate() string {
+	return " FOR UPDATE"
+}
+
+func (postgresDialect) IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// serialization_failure, deadlock_detected
+		return pqErr.Code == "40001" || pqErr.Code == "40P01"
+	}
+	return false
+}
+
+func (postgresDialect) ClassifyError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return nil
+	}
+	switch pqErr.Code {
+	case "23505":
+		return ErrUniqueViolation
+	case "23503":
+		return ErrForeignKeyViolation
+	case "23502":
+		return ErrNotNullViolation
+	case "23514":
+		return ErrCheckViolation
+	}
+	return nil
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string {
+	return "mysql"
+}
+
+func (mysqlDialect) Placeholder(int) string {
+	return "?"
+}
+
+func (mysqlDialect) Quote(ident string) string {
+	return quoteIdent(ident, "`")
+}
+
+// Upsert ignores the conflict target: mysql resolves conflicts against
+// every unique key of the table.
+func (mysqlDialect) Upsert(_ string, assignments string) (string, error) {
+	return fmt.Sprintf(`DUPLICATE KEY UPDATE %v`, assignments), nil
+}
+
+func (d mysqlDialect) Excluded(col string) string {
+	return fmt.Sprintf("VALUES(%v)", d.Quote(col))
+}
+
+func (mysqlDialect) SupportsReturning() bool {
+	return false
+}
+
+func (mysqlDialect) SupportsLastInsertId() bool {
+	return true
+}
+
+func (mysqlDialect) MaxParameters() int {
+	return 65535
+}
+
+func (mysqlDialect) ColumnType(field FieldInfo) string {
+	var t = storedType(field.Type)
+	switch {
+	case t == timeType:
+		return "DATETIME"
+	case t == bytesType:
+		return "BLOB"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN"
+	case reflect.Int8:
+		return "TINYINT"
+	case reflect.Int16:
+		return "SMALLINT"
+	case reflect.Int32:
+		return "INT"
+	case reflect.Int, reflect.Int64:
+		return "BIGINT"
+	case reflect.Uint8:
+		return "TINYINT UNSIGNED"
+	case reflect.Uint16:
+		return "SMALLINT UNSIGNED"
+	case reflect.Uint32:
+		return "INT UNSIGNED"
+	case reflect.Uint, reflect.Uint64:
+		return "BIGINT UNSIGNED"
+	case reflect.Float32:
+		return "FLOAT"
+	case reflect.Float64:
+		return "DOUBLE"
+	case reflect.String:
+		if field.PK || field.Unique || field.IndexName != "" {
+			return "VARCHAR(255)" // TEXT columns cannot be keyed without a prefix length
+		}
+		return "TEXT"
+	}
+	return ""
+}
+
+func (mysqlDialect) AutoIncrement() string {
+	return "AUTO_INCREMENT"
+}
+
+func (mysqlDialect) SupportsCreateIndexIfNotExists() bool {
+	return false
+}
+
+func (mysqlDialect) UpsertSyntax() string {
+	return "DUPLICATE KEY"
+}
+
+// Inserted counts any affected row: mysql reports 2 when an upsert updates an
+// existing row, and 0 when it leaves the row unchanged unless the DSN sets
+// clientFoundRows.
+func (mysqlDialect) Inserted(affected int64) bool {
+	return affected > 0
+}
+
+// NoLimit is the largest LIMIT, since mysql only accepts OFFSET after one.
+func (mysqlDialect) NoLimit() string {
+	return "LIMIT 18446744073709551615"
+}
+
+func (mysqlDialect) CopyIn(string, []string) (string, bool) {
+	return "", false
+}
+
+// LockTable takes a named lock, which mysql holds per session rather than
+// per transaction, so it must be released with unlock.
+func (mysqlDialect) LockTable(table string) (string, string) {
+	var name = "'" + strings.ReplaceAll(table, "'", "''") + "'"
+	return fmt.Sprintf(`SELECT GET_LOCK(%v, -1);`, name), fmt.Sprintf(`SELECT RELEASE_LOCK(%v);`, name)
+}
+
+func (mysqlDialect) ForUpdate() string {
+	return " FOR UPDATE"
+}
+
+func (mysqlDialect) IsRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		// ER_LOCK_DEADLOCK, ER_LOCK_WAIT_TIMEOUT
+		return mysqlErr.Number == 1213 || mysqlErr.Number == 1205
+	}
+	return false
+}
+
+func (mysqlDialect) ClassifyError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return nil
+	}
+	switch mysqlErr.Number {
+	case 1062:
+		return ErrUniqueViolation
+	case 1451, 1452:
+		return ErrForeignKeyViolation
+	case 1048:
+		return ErrNotNullViolation
+	case 3819:
+		return ErrCheckViolation
+	}
+	return nil
+}
+
+// quoteIdent quotes each part of a (possibly schema qualified) identifier,
+// doubling any embedded quote characters.
+func quoteIdent(ident string, q string) string {
+	var parts = strings.Split(ident, ".")
+	for i, part := range parts {
+		parts[i] = q + strings.ReplaceAll(part, q, q+q) + q
+	}
+	return strings.Join(parts, ".")
+}
+
+// conflictUpsert renders ON CONFLICT upserts, which need a conflict target.
+func conflictUpsert(dialect Dialect, conflict string, assignments string) (string, error) {
+	if strings.TrimSpace(conflict) == "" {
+		return "", fmt.Errorf("dblite: %v upserts need a conflict target, e.g. CONFLICT(id)", dialect.Name())
+	}
+	return fmt.Sprintf(`%v DO UPDATE SET %v`, conflict, assignments), nil
+}
@@ -0,0 +1,139 @@
+package dblite
+
+import (
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+func TestDialect(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test Dialect", func() {
+		g.It("lookup dialect by name", func() {
+			d, err := LookupDialect("postgres")
+			g.Assert(err).IsNil()
+			g.Assert(d.Name()).Equal("postgres")
+
+			_, err = LookupDialect("postgresql")
+			g.Assert(err == nil).IsFalse()
+		})
+
+		g.It("placeholders", func() {
+			var cols = []string{"id", "email", "name"}
+			g.Assert(ColumnPlaceholders(cols, SQLite3)).Equal("?,?,?")
+			g.Assert(ColumnPlaceholders(cols, Postgres)).Equal("$1,$2,$3")
+			g.Assert(UpdatePlaceholders(cols, SQLite3)).Equal(`"id"=?,"email"=?,"name"=?`)
+			g.Assert(UpdatePlaceholders(cols, Postgres)).Equal(`"id"=$1,"email"=$2,"name"=$3`)
+		})
+
+		g.It("quote identifiers", func() {
+			g.Assert(SQLite3.Quote("id")).Equal(`"id"`)
+			g.Assert(Postgres.Quote(`public.my"table`)).Equal(`"public"."my""table"`)
+			g.Assert(Postgres.Quote(`public.userId`)).Equal(`"public".userId`)
+			g.Assert(Postgres.Quote(`User Id`)).Equal(`"User Id"`)
+			g.Assert(ColumnNames([]string{"id", "name"})).Equal(`id,name`)
+			g.Assert(QuotedColumnNames([]string{"id", "name"}, MySQL)).Equal("`id`,`name`")
+		})
+
+		g.It("dialect of handles built as struct literals", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			g.Assert((&Database{}).Dialect().Name()).Equal("sqlite3")
+			g.Assert((&DatabaseSource{DBType: "postgres"}).Dialect().Name()).Equal("postgres")
+
+			var ds = &DatabaseSource{DBType: "sqlite3", Conn: dbInstance.Conn}
+			bln, _, err := Insert(ds, &Model{Id: 1, Email: "email@db.com"}, nil, On{})
+			g.Assert(err).IsNil()
+			g.Assert(bln).IsTrue()
+		})
+
+		g.It("dialect capabilities", func() {
+			g.Assert(MySQL.Inserted(2)).IsTrue()
+			g.Assert(Postgres.Inserted(2)).IsFalse()
+			g.Assert(Postgres.NoLimit()).Equal("")
+			_, ok := SQLite3.CopyIn("model", []string{"id"})
+			g.Assert(ok).IsFalse()
+			lock, unlock := Postgres.LockTable("schema_migrations")
+			g.Assert(lock).Equal(`SELECT pg_advisory_xact_lock(hashtext('schema_migrations'));`)
+			g.Assert(unlock).Equal("")
+			_, unlock = MySQL.LockTable("schema_migrations")
+			g.Assert(unlock).Equal(`SELECT RELEASE_LOCK('schema_migrations');`)
+		})
+
+		g.It("mysql insert, upsert and update sql", func() {
+			var m = &Model{Id: 7, Email: "email@db.com", Name: "model", Address: "123 db street"}
+			var cols = []string{"id", "email", "name", "address"}
+
+			query, values, err := insertStatement(MySQL, m, cols, On{}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal("INSERT INTO model(`id`,`email`,`name`,`address`) VALUES (?,?,?,?);")
+			g.Assert(len(values)).Equal(4)
+
+			query, values, err = insertStatement(MySQL, m, cols, On{UpsertColumns: cols[1:]}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal("INSERT INTO model(`id`,`email`,`name`,`address`) VALUES (?,?,?,?) " +
+				"ON DUPLICATE KEY UPDATE `email` = ?, `name` = ?, `address` = ?;")
+			g.Assert(len(values)).Equal(7)
+
+			query, values, err = updateStatement(MySQL, m, []string{"name", "address"}, WhereClause{
+				Where: "id=?", Arguments: []any{m.Id},
+			}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal("UPDATE model SET `name`=?,`address`=? WHERE id=?;")
+			g.Assert(len(values)).Equal(3)
+
+			query, _, err = insertStatement(MySQL, m, cols, On{On: "DUPLICATE KEY UPDATE `name` = ?", Arguments: []any{"x"}}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal("INSERT INTO model(`id`,`email`,`name`,`address`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `name` = ?;")
+
+			_, _, err = insertStatement(MySQL, m, cols, On{On: "CONFLICT(id) DO NOTHING"}, nil)
+			g.Assert(err == nil).IsFalse()
+			_, _, err = insertStatement(SQLite3, m, cols, On{On: "DUPLICATE KEY UPDATE `name` = ?", Arguments: []any{"x"}}, nil)
+			g.Assert(err == nil).IsFalse()
+		})
+
+		g.It("postgres and sqlite3 upsert sql", func() {
+			var m = &Model{Id: 7, Email: "email@db.com"}
+			var cols = []string{"id", "email"}
+			var on = On{On: "CONFLICT(id)", UpsertColumns: cols[1:]}
+
+			query, _, err := insertStatement(Postgres, m, cols, on, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`INSERT INTO model("id","email") VALUES ($1,$2) ON CONFLICT(id) DO UPDATE SET "email" = $3;`)
+
+			query, _, err = insertStatement(SQLite3, m, cols, on, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`INSERT INTO model("id","email") VALUES (?,?) ON CONFLICT(id) DO UPDATE SET "email" = ?;`)
+
+			_, _, err = insertStatement(Postgres, m, cols, On{UpsertColumns: cols[1:]}, nil)
+			g.Assert(err == nil).IsFalse()
+			_, _, err = manyOnClause(SQLite3, m, On{UpsertColumns: cols[1:]}, 0)
+			g.Assert(err == nil).IsFalse()
+		})
+
+		g.It("rebinds ? placeholders in hand written clauses", func() {
+			var m = &Model{Id: 7, Email: "email@db.com", Name: "model", Address: "123 db street"}
+			query, values, err := updateStatement(Postgres, m, []string{"name", "address"}, WhereClause{
+				Where: "id=? AND name <> 'who?' AND data ?? 'key'", Arguments: []any{m.Id},
+			}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`UPDATE model SET "name"=$1,"address"=$2 WHERE id=$3 AND name <> 'who?' AND data ? 'key';`)
+			g.Assert(len(values)).Equal(3)
+
+			query, values, err = insertStatement(Postgres, m, []string{"id", "email"}, On{
+				On: "CONFLICT(id) DO UPDATE SET email=?, name=?", Arguments: []any{m.Email, m.Name},
+			}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`INSERT INTO model("id","email") VALUES ($1,$2) ON CONFLICT(id) DO UPDATE SET email=$3, name=$4;`)
+			g.Assert(len(values)).Equal(4)
+
+			query, _, err = insertStatement(Postgres, m, []string{"id", "email", "name"}, On{
+				On: "CONFLICT(id)", UpsertColumns: []string{"name"},
+			}, []string{"id"})
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`INSERT INTO model("id","email","name") VALUES ($1,$2,$3) ON CONFLICT(id) DO UPDATE SET "name" = $4 RETURNING "id";`)
+		})
+	})
+}
@@ -0,0 +1,46 @@
+package dblite
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound              = fmt.Errorf("dblite: not found: %w", sql.ErrNoRows)
+	ErrMultipleRows          = errors.New("dblite: multiple rows")
+	ErrUniqueViolation       = errors.New("dblite: unique violation")
+	ErrForeignKeyViolation   = errors.New("dblite: foreign key violation")
+	ErrNotNullViolation      = errors.New("dblite: not null violation")
+	ErrCheckViolation        = errors.New("dblite: check violation")
+	ErrReturningNotSupported = errors.New("dblite: RETURNING is not supported")
+)
+
+type ExecError struct {
+	Index int // index of the failing record, -1 if the batch failed outside the records
+	Query string
+	Err   error // the failure joined with any rollback error
+}
+
+func (e *ExecError) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("dblite: exec: %v", e.Err)
+	}
+	return fmt.Sprintf("dblite: exec record %d: %v", e.Index, e.Err)
+}
+
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// translateError tags driver errors with the matching dblite sentinel so
+// callers can use errors.Is regardless of the driver in use.
+func translateError(dialect Dialect, err error) error {
+	if err == nil || dialect == nil {
+		return err
+	}
+	if sentinel := dialect.ClassifyError(err); sentinel != nil && !errors.Is(err, sentinel) {
+		return fmt.Errorf("%w: %w", sentinel, err)
+	}
+	return err
+}
@@ -1,44 +1,68 @@
 package dblite
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
-	"time"
+	"errors"
 )
 
-func Exec(conn *sql.DB, query string, args ...any) (sql.Result, error) {
-	return conn.Exec(query, args...)
+func Exec(conn Executor, query string, args ...any) (sql.Result, error) {
+	return ExecContext(context.Background(), conn, query, args...)
 }
 
-func ExecMany(conn *sql.DB, query string, records [][]any) (error, error) {
-	tx, err := conn.Begin()
-	if err != nil {
-		return err, nil
-	}
+func ExecContext(ctx context.Context, conn Executor, query string, args ...any) (sql.Result, error) {
+	var res, err = conn.ExecContext(ctx, query, args...)
+	return res, translateError(dialectOf(conn), err)
+}
 
-	stmt, err := tx.Prepare(query)
-	if err != nil {
-		var prepError = tx.Rollback() // rollback if prepare fails
-		return err, prepError
+func ExecMany(conn Executor, query string, records [][]any) error {
+	return ExecManyContext(context.Background(), conn, query, records)
+}
+
+func ExecManyContext(ctx context.Context, conn Executor, query string, records [][]any) error {
+	var dialect = dialectOf(conn)
+	var beginner, ok = beginnerOf(conn)
+	if !ok { // already inside a transaction owned by the caller
+		if index, err := execRecords(ctx, conn, query, records); err != nil {
+			return &ExecError{Index: index, Query: query, Err: translateError(dialect, err)}
+		}
+		return nil
 	}
-	defer stmt.Close()
 
-	for _, record := range records {
-		_, err = stmt.Exec(record...)
+	var err = retryPolicyOf(conn).Do(ctx, dialect, func() error {
+		tx, err := beginner.BeginTx(ctx, nil)
 		if err != nil {
-			var errRollback error
-			for i := 1; i <= 5; i++ {
-				errRollback = tx.Rollback()
-				if errRollback == nil {
-					break
-				} else {
-					errRollback = fmt.Errorf("failed to rollback after %d attempts: %v", i, errRollback)
-				}
-				time.Sleep(time.Second * 2)
+			return &ExecError{Index: -1, Query: query, Err: translateError(dialect, err)}
+		}
+		if index, err := execRecords(ctx, tx, query, records); err != nil {
+			var errRollback = tx.Rollback()
+			if errors.Is(errRollback, sql.ErrTxDone) {
+				errRollback = nil // already rolled back by a cancelled context
 			}
-			return err, errRollback
+			return &ExecError{Index: index, Query: query, Err: errors.Join(translateError(dialect, err), errRollback)}
 		}
+		if err = tx.Commit(); err != nil { // commit all changes at once
+			return &ExecError{Index: -1, Query: query, Err: translateError(dialect, err)}
+		}
+		return nil
+	})
+	return err
+}
+
+func execRecords(ctx context.Context, conn Executor, query string, records [][]any) (int, error) {
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return -1, err
 	}
+	defer stmt.Close()
 
-	return tx.Commit(), nil // commit all changes at once
+	for i, record := range records {
+		if err = ctx.Err(); err != nil {
+			return i, err
+		}
+		if _, err = stmt.ExecContext(ctx, record...); err != nil {
+			return i, err
+		}
+	}
+	return -1, nil
 }
@@ -0,0 +1,211 @@
+package dblite
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type FieldInfo struct {
+	Name      string // column name
+	Index     []int  // struct field index path
+	Type      reflect.Type
+	PK        bool
+	AutoIncr  bool
+	OmitEmpty bool
+	ReadOnly  bool
+
+	// schema options, used by CreateTableSQL
+	NotNull     bool
+	Unique      bool
+	Default     string // SQL default expression
+	SQLType     string // overrides the column type derived from Type
+	IndexName   string // index key, fields sharing it form a composite index
+	UniqueIndex bool
+}
+
+type modelInfo struct {
+	fields []FieldInfo
+	byName map[string]int
+}
+
+var modelInfoCache sync.Map // reflect.Type -> *modelInfo
+
+// ModelFields returns the column mapping of a model. Columns are named by the
+// `db:"name,pk,autoincr,omitempty,readonly"` tag, falling back to the json
+// tag name; untagged fields and fields tagged "-" are skipped.
+//
+// The schema options notnull, unique, default=expr, type=sqltype, index[=key]
+// and uniqueindex[=key] are also accepted; the key defaults to the column
+// name and fields sharing a key form a composite index. Option values cannot
+// contain commas.
+func ModelFields(model any) ([]FieldInfo, error) {
+	var info, err = modelInfoOf(model)
+	if err != nil {
+		return nil, err
+	}
+	return info.fields, nil
+}
+
+func modelInfoOf(model any) (*modelInfo, error) {
+	var t = reflect.TypeOf(model)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("input must be a pointer to a struct")
+	}
+	if info, ok := modelInfoCache.Load(t); ok {
+		return info.(*modelInfo), nil
+	}
+
+	var info = &modelInfo{byName: make(map[string]int)}
+	collectFields(t.Elem(), nil, info)
+	modelInfoCache.Store(t, info)
+	return info, nil
+}
+
+func collectFields(t reflect.Type, index []int, info *modelInfo) {
+	for i := 0; i < t.NumField(); i++ {
+		var sf = t.Field(i)
+		var idx = append(append(make([]int, 0, len(index)+1), index...), i)
+
+		var tag, hasTag = sf.Tag.Lookup("db")
+		if !hasTag {
+			var jsonTag, ok = sf.Tag.Lookup("json")
+			if !ok && sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+				collectFields(sf.Type, idx, info)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			tag = strings.Split(jsonTag, ",")[0] // json options do not apply to columns
+		}
+		if tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		var opts = strings.Split(tag, ",")
+		var field = FieldInfo{Name: opts[0], Index: idx, Type: sf.Type}
+		if field.Name == "" {
+			field.Name = sf.Name
+		}
+		for _, opt := range opts[1:] {
+			var key, value, _ = strings.Cut(strings.TrimSpace(opt), "=")
+			switch key {
+			case "pk":
+				field.PK = true
+			case "autoincr":
+				field.AutoIncr = true
+			case "omitempty":
+				field.OmitEmpty = true
+			case "readonly":
+				field.ReadOnly = true
+			case "notnull":
+				field.NotNull = true
+			case "unique":
+				field.Unique = true
+			case "default":
+				field.Default = value
+			case "type":
+				field.SQLType = value
+			case "index", "uniqueindex":
+				field.IndexName = cmp.Or(value, field.Name)
+				field.UniqueIndex = key == "uniqueindex"
+			}
+		}
+		if _, ok := info.byName[field.Name]; ok {
+			continue // shallower fields win, as with encoding/json
+		}
+		info.byName[field.Name] = len(info.fields)
+		info.fields = append(info.fields, field)
+	}
+}
+
+// Fields returns the column names of a model.
+func Fields(model any) ([]string, error) {
+	var info, err = modelInfoOf(model)
+	if err != nil {
+		return nil, err
+	}
+	return MapFn(info.fields, func(f FieldInfo) string { return f.Name }), nil
+}
+
+// FieldReferences returns the given columns with pointers to the matching
+// model fields, for scanning and binding.
+func FieldReferences(fields []string, model any) ([]string, []any, error) {
+	var info, err = modelInfoOf(model)
+	if err != nil {
+		return nil, nil, err
+	}
+	var v = reflect.ValueOf(model).Elem()
+	var cols = make([]string, 0, len(fields))
+	var refs = make([]any, 0, len(fields))
+	for _, name := range fields {
+		var i, ok = info.byName[name]
+		if !ok {
+			return cols, refs, fmt.Errorf("field '%s' not found", name)
+		}
+		cols = append(cols, name)
+		refs = append(refs, v.FieldByIndex(info.fields[i].Index).Addr().Interface())
+	}
+	return cols, refs, nil
+}
+
+// PrimaryKey returns the columns tagged pk.
+func PrimaryKey(model any) ([]string, error) {
+	var info, err = modelInfoOf(model)
+	if err != nil {
+		return nil, err
+	}
+	var keys = make([]string, 0, 1)
+	for _, f := range info.fields {
+		if f.PK {
+			keys = append(keys, f.Name)
+		}
+	}
+	return keys, nil
+}
+
+// insertColumns derives the columns to insert when none are given: readonly
+// columns are skipped, as are zero valued autoincr and omitempty columns.
+// With perRow false only the tag options are considered, so that every row
+// of a batch inserts the same columns.
+func insertColumns(model any, perRow bool) ([]string, error) {
+	var info, err = modelInfoOf(model)
+	if err != nil {
+		return nil, err
+	}
+	var v = reflect.ValueOf(model).Elem()
+	var cols = make([]string, 0, len(info.fields))
+	for _, f := range info.fields {
+		if f.ReadOnly {
+			continue
+		}
+		if f.AutoIncr && (!perRow || v.FieldByIndex(f.Index).IsZero()) {
+			continue
+		}
+		if f.OmitEmpty && perRow && v.FieldByIndex(f.Index).IsZero() {
+			continue
+		}
+		cols = append(cols, f.Name)
+	}
+	return cols, nil
+}
+
+// updateColumns derives the columns to update when none are given: every
+// column except primary keys, autoincr and readonly columns.
+func updateColumns(model any) ([]string, error) {
+	var info, err = modelInfoOf(model)
+	if err != nil {
+		return nil, err
+	}
+	var cols = make([]string, 0, len(info.fields))
+	for _, f := range info.fields {
+		if f.PK || f.AutoIncr || f.ReadOnly {
+			continue
+		}
+		cols = append(cols, f.Name)
+	}
+	return cols, nil
+}
@@ -0,0 +1,101 @@
+package dblite
+
+import (
+	"context"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+type Account struct {
+	Id       int64  `db:"id,pk,autoincr"`
+	Email    string `db:"email,notnull,unique"`
+	NickName string `db:"nick_name,omitempty,default='anon'"`
+	Created  string `db:"created_at,readonly,default=CURRENT_TIMESTAMP"`
+	Secret   string `db:"-" json:"secret"`
+	Ignored  string
+}
+
+func (account *Account) New() *Account {
+	return &Account{}
+}
+
+func (account *Account) Clone() *Account {
+	var o = *account
+	return &o
+}
+
+func (account *Account) TableName() string {
+	return "account"
+}
+
+func initAccountDB() {
+	initDB()
+	_, err := dbInstance.Exec(`DROP TABLE IF EXISTS account;`)
+	checkError(err)
+	checkError(CreateTable(context.Background(), dbInstance, &Account{}))
+}
+
+func TestFields(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test Fields", func() {
+		g.It("maps db tags", func() {
+			fields, err := Fields(&Account{})
+			g.Assert(err).IsNil()
+			g.Assert(fields).Equal([]string{"id", "email", "nick_name", "created_at"})
+
+			keys, err := PrimaryKey(&Account{})
+			g.Assert(err).IsNil()
+			g.Assert(keys).Equal([]string{"id"})
+
+			fields, err = Fields(&Model{})
+			g.Assert(err).IsNil()
+			g.Assert(fields).Equal([]string{"id", "email", "name", "address", "active"})
+		})
+
+		g.It("derives insert and update columns", func() {
+			var account = &Account{Email: "email@db.com"}
+			query, values, err := insertStatement(SQLite3, account, nil, On{}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`INSERT INTO account("email") VALUES (?);`)
+			g.Assert(len(values)).Equal(1)
+
+			account.Id, account.NickName = 3, "nick"
+			query, _, err = insertStatement(SQLite3, account, nil, On{}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`INSERT INTO account("id","email","nick_name") VALUES (?,?,?);`)
+
+			query, _, err = updateStatement(Postgres, account, nil, WhereClause{Where: "id=?", Arguments: []any{3}}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`UPDATE account SET "email"=$1,"nick_name"=$2 WHERE id=$3;`)
+		})
+
+		g.It("inserts and reads tagged models", func() {
+			g.Timeout(1 * time.Hour)
+			initAccountDB()
+			defer deInitDB()
+
+			var account = &Account{Email: "email@db.com"}
+			bln, id, err := Insert(dbInstance, account, nil, On{})
+			g.Assert(err).IsNil()
+			g.Assert(bln).IsTrue()
+			g.Assert(id).Equal(int64(1))
+			g.Assert(account.Id).Equal(int64(1))
+
+			bln, id, err = Insert(dbInstance, &Account{Email: "other@db.com"}, nil, On{})
+			g.Assert(err).IsNil()
+			g.Assert(id).Equal(int64(2))
+
+			bln, id, err = Insert(dbInstance, &Account{Email: "other@db.com"}, nil, On{On: "CONFLICT(email) DO NOTHING"})
+			g.Assert(err).IsNil()
+			g.Assert(bln).IsFalse()
+			g.Assert(id).Equal(int64(0))
+
+			m, err := First(dbInstance, &Account{}, WhereClause{Where: "id=?", Arguments: []any{1}})
+			g.Assert(err).IsNil()
+			g.Assert(m.Email).Equal("email@db.com")
+			g.Assert(m.NickName).Equal("anon")
+			g.Assert(m.Created == "").IsFalse()
+		})
+	})
+}
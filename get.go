@@ -0,0 +1,51 @@
+package dblite
+
+import (
+	"context"
+)
+
+func First[T ITable[T]](db DB, model T, where ...WhereClause) (T, error) {
+	return FirstContext(context.Background(), db, model, where...)
+}
+
+// FirstContext scans the first row matching where into model and returns
+// ErrNotFound when nothing matched.
+func FirstContext[T ITable[T]](ctx context.Context, db DB, model T, where ...WhereClause) (T, error) {
+	var fields, err = Fields(model)
+	if err != nil {
+		return model, err
+	}
+	count, err := queryModel(ctx, db, model, fields, 1, where...)
+	if err != nil {
+		return model, err
+	}
+	if count == 0 {
+		return model, ErrNotFound
+	}
+	return model, nil
+}
+
+func GetOne[T ITable[T]](db DB, model T, where ...WhereClause) (T, error) {
+	return GetOneContext(context.Background(), db, model, where...)
+}
+
+// GetOneContext is like FirstContext but also fails with ErrMultipleRows
+// when more than one row matched.
+func GetOneContext[T ITable[T]](ctx context.Context, db DB, model T, where ...WhereClause) (T, error) {
+	var fields, err = Fields(model)
+	if err != nil {
+		return model, err
+	}
+	count, err := queryModel(ctx, db, model, fields, 2, where...)
+	if err != nil {
+		return model, err
+	}
+	switch count {
+	case 0:
+		return model, ErrNotFound
+	case 1:
+		return model, nil
+	default:
+		return model, ErrMultipleRows
+	}
+}
@@ -4,8 +4,12 @@ go 1.24
 
 require (
 	github.com/franela/goblin v0.0.0-20211003143422-0a4f594942bf
-	github.com/intdxdt/goreflect v1.4.0
 	github.com/mattn/go-sqlite3 v1.14.28
 )
 
-require github.com/lib/pq v1.10.9
+require (
+	github.com/go-sql-driver/mysql v1.9.3
+	github.com/lib/pq v1.10.9
+)
+
+require filippo.io/edwards25519 v1.1.0 // indirect
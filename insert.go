@@ -1,21 +1,117 @@
 package dblite
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	ref "github.com/intdxdt/goreflect"
 )
 
-func Insert[T ITable[T]](conn *sql.DB, model T, insertCols []string, on On, dbType string) (bool, int64, error) {
-	var fields, err = ref.Fields(model)
+// Insert reports whether exactly one row was inserted or upserted, along with
+// the generated id. On mysql it reports any affected row, so an upsert that
+// changes nothing reports false unless the DSN sets clientFoundRows=true.
+func Insert[T ITable[T]](db DB, model T, insertCols []string, on On) (bool, int64, error) {
+	return InsertContext(context.Background(), db, model, insertCols, on)
+}
+
+func InsertContext[T ITable[T]](ctx context.Context, db DB, model T, insertCols []string, on On) (bool, int64, error) {
+	var dialect = db.Dialect()
+	var returning []string
+	if key, ok := generatedKey(model); ok && dialect.SupportsReturning() {
+		returning = []string{key}
+	}
+	var sqlStatement, values, err = insertStatement(dialect, model, insertCols, on, returning)
+	if err != nil {
+		return false, -1, err
+	}
+
+	if len(returning) > 0 { // scan the generated key straight into the model
+		inserted, err := scanReturning(ctx, db, model, sqlStatement, values, returning)
+		if err != nil {
+			return false, -1, err
+		}
+		if !inserted {
+			return false, 0, nil
+		}
+		_, refs, err := FieldReferences(returning, model)
+		if err != nil {
+			return false, -1, err
+		}
+		return true, intValue(refs[0]), nil
+	}
+
+	res, err := ExecContext(ctx, db, sqlStatement, values...)
 	if err != nil {
 		return false, -1, err
 	}
 
-	fields, colRefs, err := ref.FilterFieldReferences(fields, model)
+	count, err := res.RowsAffected()
 	if err != nil {
 		return false, -1, err
 	}
+	var insertId int64
+	if dialect.SupportsLastInsertId() {
+		insertId, err = res.LastInsertId()
+		if err != nil {
+			return false, -1, err
+		}
+		if count > 0 {
+			setAutoIncr(model, insertId)
+		}
+	}
+	return dialect.Inserted(count), insertId, nil
+}
+
+// InsertReturning inserts the model and scans the returning columns, such as
+// server side defaults, back into it. It reports false when no row was
+// inserted, e.g. on conflict do nothing.
+func InsertReturning[T ITable[T]](db DB, model T, insertCols []string, on On, returning []string) (bool, error) {
+	return InsertReturningContext(context.Background(), db, model, insertCols, on, returning)
+}
+
+func InsertReturningContext[T ITable[T]](ctx context.Context, db DB, model T, insertCols []string, on On, returning []string) (bool, error) {
+	var dialect = db.Dialect()
+	if err := checkReturning(dialect); err != nil {
+		return false, err
+	}
+	var sqlStatement, values, err = insertStatement(dialect, model, insertCols, on, returning)
+	if err != nil {
+		return false, err
+	}
+	return scanReturning(ctx, db, model, sqlStatement, values, returning)
+}
+
+func scanReturning[T ITable[T]](ctx context.Context, db DB, model T, query string, values []any, returning []string) (bool, error) {
+	var _, refs, err = FieldReferences(returning, model)
+	if err != nil {
+		return false, err
+	}
+	err = db.QueryRowContext(ctx, query, values...).Scan(refs...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, translateError(db.Dialect(), err)
+	}
+	return true, nil
+}
+
+func insertStatement[T ITable[T]](dialect Dialect, model T, insertCols []string, on On, returning []string) (string, []any, error) {
+	var fields, err = Fields(model)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(insertCols) == 0 {
+		insertCols, err = insertColumns(model, true)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	fields, colRefs, err := FieldReferences(fields, model)
+	if err != nil {
+		return "", nil, err
+	}
 
 	var getColsVals = func(inputCols []string) ([]string, []any) {
 		var cols = make([]string, 0, len(fields))
@@ -34,115 +130,144 @@ func Insert[T ITable[T]](conn *sql.DB, model T, insertCols []string, on On, dbTy
 	}
 
 	var cols, values = getColsVals(insertCols)
-	var columns = ColumnNames(cols)
-	var holders = ColumnPlaceholders(cols, dbType)
+	var columns = QuotedColumnNames(cols, dialect)
+	var holders = ColumnPlaceholders(cols, dialect)
 
-	var sqlStatement = fmt.Sprintf(`
-		INSERT INTO %v(%v) 
-		VALUES (%v);`, model.TableName(), columns, holders)
+	var sqlStatement = fmt.Sprintf(
+		`INSERT INTO %v(%v) VALUES (%v)`, model.TableName(), columns, holders)
 
-	if len(on.On) > 0 {
+	if len(on.On) > 0 || len(on.UpsertColumns) > 0 {
 		var sqlOn string
 		if len(on.UpsertColumns) > 0 { //do an upsert given upsert columns
 			var upsertCols, upsertValues = getColsVals(on.UpsertColumns)
-			var colPlaceholders = ColumnEqualParamAttributes(upsertCols, dbType)
-			sqlOn = fmt.Sprintf(`%v DO UPDATE SET %v`, on.On, colPlaceholders)
+			var colPlaceholders = ColumnEqualParamAttributes(upsertCols, dialect, len(values))
+			sqlOn, err = on.upsert(dialect, colPlaceholders)
+			if err != nil {
+				return "", nil, err
+			}
 			values = append(values, upsertValues...)
-		} else if len(on.Arguments) > 0 { //on with arguments - maybe not an upsert
-			sqlOn = on.On
-			values = append(values, on.Arguments...)
-		} else {
-			sqlOn = on.On
+		} else { //on with optional arguments - maybe not an upsert
+			var onArgs []any
+			sqlOn, onArgs, err = on.bind(dialect, len(values))
+			if err != nil {
+				return "", nil, err
+			}
+			values = append(values, onArgs...)
 		}
-		sqlStatement = fmt.Sprintf(`
-		INSERT INTO %v(%v) 
-		VALUES (%v)
-		ON %v;`, model.TableName(), columns, holders, sqlOn)
+		sqlStatement = fmt.Sprintf(
+			`INSERT INTO %v(%v) VALUES (%v) ON %v`, model.TableName(), columns, holders, sqlOn)
 	}
+	return sqlStatement + returningClause(dialect, returning) + ";", values, nil
+}
 
-	res, err := Exec(conn, sqlStatement, values...)
-	if err != nil {
-		return false, -1, err
-	}
+func InsertMany[T ITable[T]](db DB, models []T, insertCols []string, on On) error {
+	return InsertManyContext(context.Background(), db, models, insertCols, on)
+}
 
-	count, err := res.RowsAffected()
-	if err != nil {
-		return false, -1, err
+func InsertManyContext[T ITable[T]](ctx context.Context, db DB, models []T, insertCols []string, on On) error {
+	if len(models) == 0 {
+		return nil
 	}
-	var insertId int64
-	if dbType == "postgres" {
-		insertId = 0
-	} else {
-		insertId, err = res.LastInsertId()
+	if len(insertCols) == 0 {
+		var err error
+		insertCols, err = insertColumns(models[0], false)
 		if err != nil {
-			return false, -1, err
+			return err
 		}
 	}
-	return count == 1, insertId, nil
-}
+	insertCols = withUpsertColumns(insertCols, on)
 
-func InsertMany[T ITable[T]](conn *sql.DB, models []T, insertCols []string, on On, dbType string) (error, error) {
-	if len(models) == 0 {
-		return nil, nil
+	var model = models[0]
+	var cols, _, err = columnValues(model, insertCols)
+	if err != nil {
+		return err
 	}
 
-	var getColumnsValues = func(model T) ([]string, []any, error) {
-		var fields, err = ref.Fields(model)
+	var dialect = db.Dialect()
+	var columns = QuotedColumnNames(cols, dialect)
+	var holders = ColumnPlaceholders(cols, dialect)
+
+	var sqlStatement = fmt.Sprintf(
+		`INSERT INTO %v(%v) VALUES (%v);`, model.TableName(), columns, holders)
+
+	var onArgs []any
+	if len(on.On) > 0 || len(on.UpsertColumns) > 0 {
+		var sqlOn string
+		sqlOn, onArgs, err = manyOnClause(dialect, model, on, len(cols))
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
+		sqlStatement = fmt.Sprintf(
+			`INSERT INTO %v(%v) VALUES (%v) ON %v;`, model.TableName(), columns, holders, sqlOn)
+	}
 
-		fields, colRefs, err := ref.FilterFieldReferences(fields, model)
+	var records = make([][]any, 0, len(models))
+	for _, model = range models {
+		_, values, err := columnValues(model, insertCols)
 		if err != nil {
-			return nil, nil, err
+			return err
 		}
-		var cols = make([]string, 0, len(fields))
-		var values = make([]any, 0, len(fields))
+		values = append(values, onArgs...)
+		records = append(records, values)
+	}
 
-		var dict = KeysToMap(insertCols, true)
+	return ExecManyContext(ctx, db, sqlStatement, records)
+}
 
-		for i, field := range fields {
-			if !dict[field] {
-				continue
-			}
-			cols = append(cols, field)
-			values = append(values, colRefs[i])
+// manyOnClause renders the On clause shared by every row of a batch: upsert
+// columns are assigned from the row being inserted so no extra arguments are
+// needed, matching Insert which binds the model values a second time. The
+// insert columns must include the upsert columns, see withUpsertColumns.
+func manyOnClause(dialect Dialect, model any, on On, offset int) (string, []any, error) {
+	if len(on.UpsertColumns) > 0 {
+		var upsertCols, _, err = columnValues(model, on.UpsertColumns)
+		if err != nil {
+			return "", nil, err
 		}
-		return cols, values, nil
-	}
-	var model = models[0]
-	var cols, _, err = getColumnsValues(model)
-	if err != nil {
-		return err, nil
+		sqlOn, err := on.upsert(dialect, ColumnEqualExcluded(upsertCols, dialect))
+		return sqlOn, nil, err
 	}
+	return on.bind(dialect, offset)
+}
 
-	var columns = ColumnNames(cols)
-	var holders = ColumnPlaceholders(cols, dbType)
+// withUpsertColumns adds the upsert columns missing from insertCols, since a
+// batch upsert assigns them from the inserted row and a column left out of
+// the insert would be assigned its default.
+func withUpsertColumns(insertCols []string, on On) []string {
+	var dict = KeysToMap(insertCols, true)
+	var cols = append(make([]string, 0, len(insertCols)+len(on.UpsertColumns)), insertCols...)
+	for _, col := range on.UpsertColumns {
+		if !dict[col] {
+			dict[col] = true
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
 
-	var sqlStatement = fmt.Sprintf(`
-		INSERT INTO %v(%v) 
-		VALUES (%v);`, model.TableName(), columns, holders)
+// columnValues returns the model's columns among insertCols, in field order,
+// with references to their values.
+func columnValues(model any, insertCols []string) ([]string, []any, error) {
+	var fields, err = Fields(model)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if len(on.On) > 0 {
-		sqlStatement = fmt.Sprintf(`
-		INSERT INTO %v(%v) 
-		VALUES (%v)
-		ON %v;`, model.TableName(), columns, holders, on.On)
+	fields, colRefs, err := FieldReferences(fields, model)
+	if err != nil {
+		return nil, nil, err
 	}
+	var cols = make([]string, 0, len(fields))
+	var values = make([]any, 0, len(fields))
 
-	var records = make([][]any, 0, len(models))
-	for _, model = range models {
-		_, values, err := getColumnsValues(model)
-		if err != nil {
-			return err, nil
-		}
-		if len(on.On) > 0 {
-			for _, v := range on.Arguments {
-				values = append(values, v)
-			}
+	var dict = KeysToMap(insertCols, true)
+
+	for i, field := range fields {
+		if !dict[field] {
+			continue
 		}
-		records = append(records, values)
+		cols = append(cols, field)
+		values = append(values, colRefs[i])
 	}
-
-	return ExecMany(conn, sqlStatement, records)
+	return cols, values, nil
 }
@@ -0,0 +1,241 @@
+package dblite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+)
+
+type ColumnInfo struct {
+	Name    string
+	Type    string // declared type, as reported by the database
+	NotNull bool
+	Default sql.NullString // default expression
+	PK      int            // position in the primary key, 0 if not part of it
+}
+
+type IndexInfo struct {
+	Name    string
+	Unique  bool
+	Primary bool
+	Columns []string
+}
+
+type ForeignKeyInfo struct {
+	Name       string // empty on sqlite, where constraints are unnamed
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnUpdate   string
+	OnDelete   string
+}
+
+var errNoIntrospection = errors.New("dblite: schema introspection is not supported")
+
+func errIntrospection(dialect Dialect) error {
+	return fmt.Errorf("%w on %v", errNoIntrospection, dialect.Name())
+}
+
+func errNoTable(table string) error {
+	return fmt.Errorf("%w: table %q", ErrNotFound, table)
+}
+
+// introspector is implemented by the dialects whose catalog dblite can read.
+type introspector interface {
+	tablesQuery() string
+	columnsQuery() string
+	indexes(ctx context.Context, db DB, table string) ([]IndexInfo, error)
+	foreignKeys(ctx context.Context, db DB, table string) ([]ForeignKeyInfo, error)
+}
+
+func introspectorOf(dialect Dialect) (introspector, error) {
+	if in, ok := dialect.(introspector); ok {
+		return in, nil
+	}
+	return nil, errIntrospection(dialect)
+}
+
+// Tables lists the tables of the current database or schema.
+func Tables(ctx context.Context, db DB) ([]string, error) {
+	var in, err = introspectorOf(db.Dialect())
+	if err != nil {
+		return nil, err
+	}
+	return scanRows(ctx, db, in.tablesQuery(), nil, func(rows *sql.Rows) (string, error) {
+		var name string
+		return name, rows.Scan(&name)
+	})
+}
+
+// Columns lists the columns of table in definition order, or ErrNotFound if
+// the table does not exist.
+func Columns(ctx context.Context, db DB, table string) ([]ColumnInfo, error) {
+	var in, err = introspectorOf(db.Dialect())
+	if err != nil {
+		return nil, err
+	}
+	columns, err := scanRows(ctx, db, in.columnsQuery(), []any{table}, func(rows *sql.Rows) (ColumnInfo, error) {
+		var c ColumnInfo
+		return c, rows.Scan(&c.Name, &c.Type, &c.NotNull, &c.Default, &c.PK)
+	})
+	if err == nil && len(columns) == 0 {
+		return nil, errNoTable(table)
+	}
+	return columns, err
+}
+
+// Indexes lists the indexes of table, including those backing primary key
+// and unique constraints.
+func Indexes(ctx context.Context, db DB, table string) ([]IndexInfo, error) {
+	var in, err = introspectorOf(db.Dialect())
+	if err != nil {
+		return nil, err
+	}
+	return in.indexes(ctx, db, table)
+}
+
+// ForeignKeys lists the foreign key constraints of table.
+func ForeignKeys(ctx context.Context, db DB, table string) ([]ForeignKeyInfo, error) {
+	var in, err = introspectorOf(db.Dialect())
+	if err != nil {
+		return nil, err
+	}
+	return in.foreignKeys(ctx, db, table)
+}
+
+func (sqlite3Dialect) tablesQuery() string {
+	return `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name;`
+}
+
+func (sqlite3Dialect) columnsQuery() string {
+	return `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid;`
+}
+
+func (sqlite3Dialect) indexes(ctx context.Context, db DB, table string) ([]IndexInfo, error) {
+	var indexes, err = scanRows(ctx, db,
+		`SELECT name, "unique", origin = 'pk' FROM pragma_index_list(?) ORDER BY name;`, []any{table},
+		func(rows *sql.Rows) (IndexInfo, error) {
+			var idx IndexInfo
+			return idx, rows.Scan(&idx.Name, &idx.Unique, &idx.Primary)
+		})
+	if err != nil {
+		return nil, err
+	}
+	for i := range indexes {
+		indexes[i].Columns, err = scanRows(ctx, db,
+			`SELECT name FROM pragma_index_info(?) ORDER BY seqno;`, []any{indexes[i].Name},
+			func(rows *sql.Rows) (string, error) {
+				var name string
+				return name, rows.Scan(&name)
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return indexes, nil
+}
+
+func (sqlite3Dialect) foreignKeys(ctx context.Context, db DB, table string) ([]ForeignKeyInfo, error) {
+	type fkColumn struct {
+		id       int
+		fk       ForeignKeyInfo
+		from, to string
+	}
+	var cols, err = scanRows(ctx, db,
+		`SELECT id, "table", "from", COALESCE("to", ''), on_update, on_delete `+
+			`FROM pragma_foreign_key_list(?) ORDER BY id, seq;`, []any{table},
+		func(rows *sql.Rows) (fkColumn, error) {
+			var c fkColumn
+			return c, rows.Scan(&c.id, &c.fk.RefTable, &c.from, &c.to, &c.fk.OnUpdate, &c.fk.OnDelete)
+		})
+	if err != nil {
+		return nil, err
+	}
+	var keys = make([]ForeignKeyInfo, 0)
+	for i, c := range cols {
+		if i == 0 || cols[i-1].id != c.id {
+			keys = append(keys, c.fk)
+		}
+		var fk = &keys[len(keys)-1]
+		fk.Columns = append(fk.Columns, c.from)
+		if c.to != "" { // empty when referencing the parent's primary key
+			fk.RefColumns = append(fk.RefColumns, c.to)
+		}
+	}
+	return keys, nil
+}
+
+func (postgresDialect) tablesQuery() string {
+	return `SELECT table_name FROM information_schema.tables ` +
+		`WHERE table_schema = current_schema() AND table_type = 'BASE TABLE' ORDER BY table_name;`
+}
+
+func (postgresDialect) columnsQuery() string {
+	return `SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull, ` +
+		`COALESCE(pg_get_expr(d.adbin, d.adrelid), CASE WHEN a.attidentity <> '' THEN 'identity' END), ` +
+		`COALESCE(array_position(i.indkey::int2[], a.attnum), 0) ` +
+		`FROM pg_attribute a ` +
+		`LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum ` +
+		`LEFT JOIN pg_index i ON i.indrelid = a.attrelid AND i.indisprimary ` +
+		`WHERE a.attrelid = to_regclass($1) AND a.attnum > 0 AND NOT a.attisdropped ORDER BY a.attnum;`
+}
+
+func (postgresDialect) indexes(ctx context.Context, db DB, table string) ([]IndexInfo, error) {
+	return scanRows(ctx, db,
+		`SELECT c.relname, i.indisunique, i.indisprimary, `+
+			`ARRAY(SELECT a.attname::text FROM unnest(i.indkey) WITH ORDINALITY AS k(attnum, n) `+
+			`JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum ORDER BY k.n) `+
+			`FROM pg_index i JOIN pg_class c ON c.oid = i.indexrelid `+
+			`WHERE i.indrelid = to_regclass($1) ORDER BY c.relname;`, []any{table},
+		func(rows *sql.Rows) (IndexInfo, error) {
+			var idx IndexInfo
+			return idx, rows.Scan(&idx.Name, &idx.Unique, &idx.Primary, pq.Array(&idx.Columns))
+		})
+}
+
+var pgReferentialActions = map[string]string{
+	"a": "NO ACTION", "r": "RESTRICT", "c": "CASCADE", "n": "SET NULL", "d": "SET DEFAULT",
+}
+
+func (postgresDialect) foreignKeys(ctx context.Context, db DB, table string) ([]ForeignKeyInfo, error) {
+	var keys, err = scanRows(ctx, db,
+		`SELECT c.conname, `+
+			`ARRAY(SELECT a.attname::text FROM unnest(c.conkey) WITH ORDINALITY AS k(attnum, n) `+
+			`JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum ORDER BY k.n), `+
+			`c.confrelid::regclass::text, `+
+			`ARRAY(SELECT a.attname::text FROM unnest(c.confkey) WITH ORDINALITY AS k(attnum, n) `+
+			`JOIN pg_attribute a ON a.attrelid = c.confrelid AND a.attnum = k.attnum ORDER BY k.n), `+
+			`c.confupdtype, c.confdeltype `+
+			`FROM pg_constraint c WHERE c.contype = 'f' AND c.conrelid = to_regclass($1) ORDER BY c.conname;`, []any{table},
+		func(rows *sql.Rows) (ForeignKeyInfo, error) {
+			var fk ForeignKeyInfo
+			return fk, rows.Scan(&fk.Name, pq.Array(&fk.Columns), &fk.RefTable, pq.Array(&fk.RefColumns), &fk.OnUpdate, &fk.OnDelete)
+		})
+	for i := range keys {
+		keys[i].OnUpdate = pgReferentialActions[keys[i].OnUpdate]
+		keys[i].OnDelete = pgReferentialActions[keys[i].OnDelete]
+	}
+	return keys, err
+}
+
+func scanRows[T any](ctx context.Context, db DB, query string, args []any, scan func(*sql.Rows) (T, error)) ([]T, error) {
+	var results = make([]T, 0)
+	rows, err := QueryContext(ctx, db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var v, err = scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, v)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return results, nil
+}
@@ -0,0 +1,91 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+const sqlEventTag = `
+DROP TABLE IF EXISTS event_tag;
+CREATE TABLE event_tag (
+	tenant   TEXT NOT NULL,
+	event_id INTEGER NOT NULL,
+	tag      TEXT NOT NULL DEFAULT 'none',
+	FOREIGN KEY (tenant, event_id) REFERENCES event (tenant, id) ON DELETE CASCADE
+);
+`
+
+func TestIntrospect(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test Introspect", func() {
+		g.It("lists tables, columns, indexes and foreign keys", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var ctx = context.Background()
+			_, err := dbInstance.Exec(`DROP TABLE IF EXISTS event;`)
+			g.Assert(err).IsNil()
+			g.Assert(CreateTable(ctx, dbInstance, &Event{})).IsNil()
+			_, err = dbInstance.Exec(sqlEventTag)
+			g.Assert(err).IsNil()
+
+			tables, err := dbInstance.Tables(ctx)
+			g.Assert(err).IsNil()
+			g.Assert(len(tables) >= 3).IsTrue()
+			g.Assert(tables[0] < tables[1]).IsTrue()
+
+			columns, err := dbInstance.Columns(ctx, "event_tag")
+			g.Assert(err).IsNil()
+			g.Assert(len(columns)).Equal(3)
+			g.Assert(columns[0].Name).Equal("tenant")
+			g.Assert(columns[1].Type).Equal("INTEGER")
+			g.Assert(columns[2].NotNull).IsTrue()
+			g.Assert(columns[2].Default.String).Equal("'none'")
+
+			columns, err = Columns(ctx, dbInstance, "event")
+			g.Assert(err).IsNil()
+			g.Assert(columns[0].PK).Equal(1)
+			g.Assert(columns[1].PK).Equal(2)
+			g.Assert(columns[2].PK).Equal(0)
+			g.Assert(columns[4].Default.Valid).IsTrue()
+
+			_, err = Columns(ctx, dbInstance, "no_such_table")
+			g.Assert(errors.Is(err, ErrNotFound)).IsTrue()
+
+			indexes, err := Indexes(ctx, dbInstance, "event")
+			g.Assert(err).IsNil()
+			var byName = make(map[string]IndexInfo)
+			for _, idx := range indexes {
+				byName[idx.Name] = idx
+			}
+			g.Assert(byName["event_kind_at_idx"].Columns).Equal([]string{"kind", "at"})
+			g.Assert(byName["event_kind_at_idx"].Unique).IsFalse()
+			g.Assert(byName["event_ref_uniq"].Unique).IsTrue()
+			var primary = 0
+			for _, idx := range indexes {
+				if idx.Primary {
+					primary++
+					g.Assert(idx.Columns).Equal([]string{"tenant", "id"})
+				}
+			}
+			g.Assert(primary).Equal(1)
+
+			keys, err := ForeignKeys(ctx, dbInstance, "event_tag")
+			g.Assert(err).IsNil()
+			g.Assert(keys).Equal([]ForeignKeyInfo{{
+				Columns:    []string{"tenant", "event_id"},
+				RefTable:   "event",
+				RefColumns: []string{"tenant", "id"},
+				OnUpdate:   "NO ACTION",
+				OnDelete:   "CASCADE",
+			}})
+
+			_, err = Tables(ctx, Bind(dbInstance.Conn, MySQL))
+			g.Assert(err == nil).IsFalse()
+		})
+	})
+}
@@ -0,0 +1,325 @@
+package dblite
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+)
+
+var ErrChecksumMismatch = errors.New("dblite: migration checksum mismatch")
+
+type MigrationFunc func(ctx context.Context, tx *Tx) error
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       MigrationFunc
+	Down     MigrationFunc // nil when the migration cannot be reverted
+	Checksum string        // of the SQL source, empty for Go migrations
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // applied with a different checksum
+	Missing   bool // applied but no longer known to the migrator
+}
+
+type schemaMigration struct {
+	Version   int64     `db:"version,pk"`
+	Name      string    `db:"name,notnull"`
+	Checksum  string    `db:"checksum,notnull"`
+	AppliedAt time.Time `db:"applied_at,notnull"`
+}
+
+var migrationColumns = []string{"version", "name", "checksum", "applied_at"}
+
+func (m *schemaMigration) New() *schemaMigration {
+	return &schemaMigration{}
+}
+
+func (m *schemaMigration) Clone() *schemaMigration {
+	var o = *m
+	return &o
+}
+
+func (m *schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies numbered migrations, recording each applied version in
+// the schema_migrations table. Every migration runs in its own transaction
+// holding the migration lock, so concurrent runs apply each migration once.
+type Migrator struct {
+	db         DB
+	migrations map[int64]Migration
+}
+
+func NewMigrator(db DB) *Migrator {
+	return &Migrator{db: db, migrations: make(map[int64]Migration)}
+}
+
+func (m *Migrator) Register(migration Migration) error {
+	if migration.Up == nil {
+		return fmt.Errorf("dblite: migration %d has no up step", migration.Version)
+	}
+	if _, ok := m.migrations[migration.Version]; ok {
+		return fmt.Errorf("dblite: duplicate migration version %d", migration.Version)
+	}
+	m.migrations[migration.Version] = migration
+	return nil
+}
+
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// RegisterFS registers the SQL migrations in the root of fsys, named
+// <version>_<name>.up.sql with an optional <version>_<name>.down.sql.
+func (m *Migrator) RegisterFS(fsys fs.FS) error {
+	var entries, err = fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	var sources = make(map[int64]map[string]string)
+	var names = make(map[int64]string)
+	for _, entry := range entries {
+		var match = migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		if name, ok := names[version]; ok && name != match[2] {
+			return fmt.Errorf("dblite: duplicate migration version %d", version)
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return err
+		}
+		names[version] = match[2]
+		if sources[version] == nil {
+			sources[version] = make(map[string]string)
+		}
+		sources[version][match[3]] = string(data)
+	}
+
+	for version, src := range sources {
+		if _, ok := src["up"]; !ok {
+			return fmt.Errorf("dblite: migration %d has no up file", version)
+		}
+		var sum = sha256.Sum256([]byte(src["up"] + "\x00" + src["down"]))
+		var migration = Migration{
+			Version:  version,
+			Name:     names[version],
+			Up:       sqlMigration(src["up"]),
+			Checksum: hex.EncodeToString(sum[:]),
+		}
+		if down, ok := src["down"]; ok {
+			migration.Down = sqlMigration(down)
+		}
+		if err = m.Register(migration); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sqlMigration(query string) MigrationFunc {
+	return func(ctx context.Context, tx *Tx) error {
+		_, err := ExecContext(ctx, tx, query)
+		return err
+	}
+}
+
+func (m *Migrator) sorted() []Migration {
+	var migrations = make([]Migration, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		migrations = append(migrations, migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	return migrations
+}
+
+// applied loads the applied migrations, creating the schema_migrations table
+// when create is set and otherwise reporting none while it does not exist,
+// where the dialect can tell.
+func (m *Migrator) applied(ctx context.Context, create bool) (map[int64]*schemaMigration, error) {
+	var table = &schemaMigration{}
+	if create {
+		if err := CreateTable(ctx, m.db, table); err != nil {
+			return nil, err
+		}
+	} else if _, err := Columns(ctx, m.db, table.TableName()); errors.Is(err, ErrNotFound) {
+		return map[int64]*schemaMigration{}, nil
+	} else if err != nil && !errors.Is(err, errNoIntrospection) {
+		return nil, err
+	}
+	var records, err = QueryModelsContext(ctx, m.db, table)
+	if err != nil {
+		return nil, err
+	}
+	var applied = make(map[int64]*schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// verify fails when an applied migration was edited since it was applied.
+func (m *Migrator) verify(applied map[int64]*schemaMigration) error {
+	for _, migration := range m.sorted() {
+		var record, ok = applied[migration.Version]
+		if ok && migration.Checksum != "" && record.Checksum != migration.Checksum {
+			return fmt.Errorf("%w: version %d (%v)", ErrChecksumMismatch, migration.Version, migration.Name)
+		}
+	}
+	return nil
+}
+
+// transact runs fn in a transaction holding the migration lock, telling it
+// whether version is applied once the lock is held.
+func (m *Migrator) transact(ctx context.Context, version int64, fn func(tx *Tx, applied bool) error) error {
+	return WithTx(ctx, m.db, func(tx *Tx) error {
+		var dialect = tx.Dialect()
+		var table = (&schemaMigration{}).TableName()
+		var lock, unlock = dialect.LockTable(table)
+		if unlock != "" {
+			defer func() { _, _ = ExecContext(ctx, tx, unlock) }()
+		}
+		if _, err := ExecContext(ctx, tx, lock); err != nil {
+			return err
+		}
+
+		// a locking read waits for a run still recording the version where the
+		// lock is released early, as with mysql, which also commits DDL implicitly
+		var query = fmt.Sprintf(`SELECT version FROM %v WHERE version = %v%v;`,
+			table, dialect.Placeholder(1), dialect.ForUpdate())
+		rows, err := QueryContext(ctx, tx, query, version)
+		if err != nil {
+			return err
+		}
+		var applied = rows.Next()
+		if err = errors.Join(rows.Err(), rows.Close()); err != nil {
+			return err
+		}
+		return fn(tx, applied)
+	})
+}
+
+// Up applies every pending migration in version order.
+func (m *Migrator) Up(ctx context.Context) error {
+	var applied, err = m.applied(ctx, true)
+	if err != nil {
+		return err
+	}
+	if err = m.verify(applied); err != nil {
+		return err
+	}
+	for _, migration := range m.sorted() {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		err = m.transact(ctx, migration.Version, func(tx *Tx, applied bool) error {
+			if applied { // by a concurrent run
+				return nil
+			}
+			if err := migration.Up(ctx, tx); err != nil {
+				return err
+			}
+			var record = &schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum,
+				AppliedAt: time.Now().UTC(),
+			}
+			_, _, err := InsertContext(ctx, tx, record, migrationColumns, On{})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("dblite: migration %d (%v): %w", migration.Version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// Down reverts the last n applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	if n < 0 {
+		return fmt.Errorf("dblite: cannot revert %d migrations", n)
+	}
+	var applied, err = m.applied(ctx, true)
+	if err != nil {
+		return err
+	}
+	if err = m.verify(applied); err != nil {
+		return err
+	}
+	var versions = make([]int64, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	slices.Sort(versions)
+	slices.Reverse(versions)
+
+	for _, version := range versions[:min(n, len(versions))] {
+		var migration, ok = m.migrations[version]
+		if !ok {
+			return fmt.Errorf("dblite: applied migration %d is unknown", version)
+		}
+		if migration.Down == nil {
+			return fmt.Errorf("dblite: migration %d (%v) cannot be reverted", version, migration.Name)
+		}
+		err = m.transact(ctx, version, func(tx *Tx, applied bool) error {
+			if !applied { // reverted by a concurrent run
+				return nil
+			}
+			if err := migration.Down(ctx, tx); err != nil {
+				return err
+			}
+			_, err := DeleteByPK(ctx, tx, &schemaMigration{Version: version})
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("dblite: migration %d (%v): %w", version, migration.Name, err)
+		}
+	}
+	return nil
+}
+
+// Status lists the known and applied migrations in version order. It does not
+// write to the database, so nothing is applied before the first Up.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var applied, err = m.applied(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	var status = make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.sorted() {
+		var s = MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			s.Applied, s.AppliedAt = true, record.AppliedAt
+			s.Modified = migration.Checksum != "" && record.Checksum != migration.Checksum
+		}
+		status = append(status, s)
+	}
+	for version, record := range applied {
+		if _, ok := m.migrations[version]; !ok {
+			status = append(status, MigrationStatus{
+				Version: version, Name: record.Name, Applied: true, AppliedAt: record.AppliedAt, Missing: true,
+			})
+		}
+	}
+	slices.SortFunc(status, func(a, b MigrationStatus) int { return cmp.Compare(a.Version, b.Version) })
+	return status, nil
+}
@@ -0,0 +1,180 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/franela/goblin"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMigrate(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test Migrate", func() {
+		g.It("applies and reverts migrations", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			_, err := dbInstance.Exec(`DROP TABLE IF EXISTS note; DROP TABLE IF EXISTS schema_migrations;`)
+			g.Assert(err).IsNil()
+
+			var ctx = context.Background()
+			var migrations = fstest.MapFS{
+				"0001_create_note.up.sql":   {Data: []byte(`CREATE TABLE note (id INTEGER PRIMARY KEY, body TEXT);`)},
+				"0001_create_note.down.sql": {Data: []byte(`DROP TABLE note;`)},
+				"0002_add_tag.up.sql":       {Data: []byte(`ALTER TABLE note ADD COLUMN tag TEXT;`)},
+				"README.md":                 {Data: []byte(`not a migration`)},
+			}
+			var seeded = false
+			var migrator = NewMigrator(dbInstance)
+			g.Assert(migrator.RegisterFS(migrations)).IsNil()
+			g.Assert(migrator.Register(Migration{
+				Version: 3,
+				Name:    "seed_note",
+				Up: func(ctx context.Context, tx *Tx) error {
+					seeded = true
+					_, err := ExecContext(ctx, tx, `INSERT INTO note (id, body, tag) VALUES (1, 'hello', 'greeting');`)
+					return err
+				},
+				Down: func(ctx context.Context, tx *Tx) error {
+					_, err := ExecContext(ctx, tx, `DELETE FROM note;`)
+					return err
+				},
+			})).IsNil()
+			g.Assert(migrator.Register(Migration{Version: 3, Up: func(context.Context, *Tx) error { return nil }}) == nil).IsFalse()
+
+			status, err := migrator.Status(ctx)
+			g.Assert(err).IsNil()
+			g.Assert(len(status)).Equal(3)
+			g.Assert(status[0].Applied).IsFalse()
+			tables, err := dbInstance.Tables(ctx)
+			g.Assert(err).IsNil()
+			g.Assert(slices.Contains(tables, "schema_migrations")).IsFalse() // status is read only
+
+			g.Assert(migrator.Up(ctx)).IsNil()
+			g.Assert(seeded).IsTrue()
+			status, err = migrator.Status(ctx)
+			g.Assert(err).IsNil()
+			g.Assert(len(status)).Equal(3)
+			for _, s := range status {
+				g.Assert(s.Applied).IsTrue()
+				g.Assert(s.Modified).IsFalse()
+			}
+			g.Assert(status[1].Name).Equal("add_tag")
+
+			seeded = false
+			g.Assert(migrator.Up(ctx)).IsNil() // nothing pending
+			g.Assert(seeded).IsFalse()
+
+			g.Assert(migrator.Down(ctx, 1)).IsNil()
+			var count int
+			g.Assert(dbInstance.Conn.QueryRow(`SELECT count(*) FROM note`).Scan(&count)).IsNil()
+			g.Assert(count).Equal(0)
+
+			g.Assert(migrator.Down(ctx, -1) == nil).IsFalse()
+
+			// 0002 has no down file, nothing is reverted past it
+			g.Assert(migrator.Down(ctx, 2) == nil).IsFalse()
+			status, err = migrator.Status(ctx)
+			g.Assert(err).IsNil()
+			g.Assert(status[1].Applied).IsTrue()
+			g.Assert(status[2].Applied).IsFalse()
+
+			// a failing migration is rolled back with its bookkeeping
+			var failing = NewMigrator(dbInstance)
+			g.Assert(failing.RegisterFS(migrations)).IsNil()
+			g.Assert(failing.Register(Migration{Version: 3, Name: "broken", Up: sqlMigration(
+				`INSERT INTO note (id, body) VALUES (2, 'x'); INSERT INTO missing VALUES (1);`)})).IsNil()
+			g.Assert(failing.Up(ctx) == nil).IsFalse()
+			g.Assert(dbInstance.Conn.QueryRow(`SELECT count(*) FROM note`).Scan(&count)).IsNil()
+			g.Assert(count).Equal(0)
+
+			// edited migrations are detected
+			migrations["0001_create_note.up.sql"] = &fstest.MapFile{Data: []byte(`CREATE TABLE note (id INTEGER PRIMARY KEY);`)}
+			var edited = NewMigrator(dbInstance)
+			g.Assert(edited.RegisterFS(migrations)).IsNil()
+			g.Assert(errors.Is(edited.Up(ctx), ErrChecksumMismatch)).IsTrue()
+			status, err = edited.Status(ctx)
+			g.Assert(err).IsNil()
+			g.Assert(status[0].Modified).IsTrue()
+
+			// dialects without introspection read the table directly
+			status, err = NewMigrator(Bind(dbInstance.Conn, MySQL)).Status(ctx)
+			g.Assert(err).IsNil()
+			g.Assert(len(status)).Equal(2)
+		})
+
+		g.It("records version zero", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			_, err := dbInstance.Exec(`DROP TABLE IF EXISTS schema_migrations;`)
+			g.Assert(err).IsNil()
+
+			var ctx = context.Background()
+			var runs = 0
+			var migrator = NewMigrator(dbInstance)
+			g.Assert(migrator.Register(Migration{Version: 0, Name: "init", Up: func(context.Context, *Tx) error {
+				runs++
+				return nil
+			}})).IsNil()
+			g.Assert(migrator.Up(ctx)).IsNil()
+			g.Assert(migrator.Up(ctx)).IsNil()
+			g.Assert(runs).Equal(1)
+
+			status, err := migrator.Status(ctx)
+			g.Assert(err).IsNil()
+			g.Assert(len(status)).Equal(1)
+			g.Assert(status[0].Version).Equal(int64(0))
+			g.Assert(status[0].Applied).IsTrue()
+		})
+
+		g.It("applies migrations once across concurrent runs", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			_, err := dbInstance.Exec(`DROP TABLE IF EXISTS note; DROP TABLE IF EXISTS schema_migrations;`)
+			g.Assert(err).IsNil()
+
+			var ctx = context.Background()
+			var runs atomic.Int32
+			var errs = make([]error, 4)
+			var wg sync.WaitGroup
+			for i := range errs {
+				db, err := NewDatabase("./bin/test.db?_busy_timeout=5000")
+				g.Assert(err).IsNil()
+				defer db.Close()
+
+				var migrator = NewMigrator(db)
+				g.Assert(migrator.Register(Migration{
+					Version: 1,
+					Name:    "create_note",
+					Up: func(ctx context.Context, tx *Tx) error {
+						runs.Add(1)
+						time.Sleep(10 * time.Millisecond)
+						_, err := ExecContext(ctx, tx, `CREATE TABLE note (id INTEGER PRIMARY KEY, body TEXT);`)
+						return err
+					},
+				})).IsNil()
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs[i] = migrator.Up(ctx)
+				}()
+			}
+			wg.Wait()
+			for i, err := range errs {
+				g.Assert(err == nil).IsTrue(fmt.Sprintf("run %d: %v", i, err))
+			}
+			g.Assert(runs.Load()).Equal(int32(1))
+		})
+	})
+}
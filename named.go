@@ -0,0 +1,111 @@
+package dblite
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// expandNamed replaces the :name parameters of query with ? placeholders and
+// returns the matching positional arguments. Named values come from a map or
+// a struct, named by db tag with the json tag as fallback; slices expand to
+// one placeholder per element.
+func expandNamed(query string, named any) (string, []any, error) {
+	var lookup, err = namedLookup(named)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var sb strings.Builder
+	var args = make([]any, 0)
+	var quote rune = 0
+	var runes = []rune(query)
+	for i := 0; i < len(runes); i++ {
+		var ch = runes[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == ':' && i+1 < len(runes) && runes[i+1] == ':': // postgres cast
+			sb.WriteString("::")
+			i++
+			continue
+		case ch == ':' && i > 0 && (runes[i-1] == '[' || runes[i-1] >= '0' && runes[i-1] <= '9'):
+			// array slice such as arr[1:2] or arr[:n]
+		case ch == ':' && i+1 < len(runes) && isNameRune(runes[i+1]):
+			var j = i + 1
+			for j < len(runes) && isNameRune(runes[j]) {
+				j++
+			}
+			var name = string(runes[i+1 : j])
+			val, ok := lookup(name)
+			if !ok {
+				return "", nil, fmt.Errorf("named parameter %q not found", name)
+			}
+			var vals = expandSlice(val)
+			if len(vals) == 0 {
+				sb.WriteString("NULL")
+			} else {
+				sb.WriteString(strings.TrimRight(strings.Repeat("?,", len(vals)), ","))
+			}
+			args = append(args, vals...)
+			i = j - 1
+			continue
+		}
+		sb.WriteRune(ch)
+	}
+	return sb.String(), args, nil
+}
+
+func isNameRune(ch rune) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+func namedLookup(named any) (func(string) (any, bool), error) {
+	if dict, ok := named.(map[string]any); ok {
+		return func(name string) (any, bool) {
+			var val, ok = dict[name]
+			return val, ok
+		}, nil
+	}
+
+	var v = reflect.ValueOf(named)
+	if v.Kind() == reflect.Struct { // FieldReferences needs an addressable struct
+		var ptr = reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		v = ptr
+	}
+	fields, err := Fields(v.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("named parameters must be a map[string]any or a struct: %v", err)
+	}
+	_, refs, err := FieldReferences(fields, v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var dict = make(map[string]any, len(fields))
+	for i, field := range fields {
+		dict[field] = reflect.ValueOf(refs[i]).Elem().Interface()
+	}
+	return func(name string) (any, bool) {
+		var val, ok = dict[name]
+		return val, ok
+	}, nil
+}
+
+// expandSlice flattens slice values used with IN (:name), leaving []byte and
+// scalars as single arguments.
+func expandSlice(val any) []any {
+	var v = reflect.ValueOf(val)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return []any{val}
+	}
+	var vals = make([]any, v.Len())
+	for i := range vals {
+		vals[i] = v.Index(i).Interface()
+	}
+	return vals
+}
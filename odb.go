@@ -1,7 +1,9 @@
 package dblite
 
 import (
+	"context"
 	"database/sql"
+	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -10,9 +12,16 @@ type DatabaseSource struct {
 	DBType string
 	URI    string
 	Conn   *sql.DB
+	Retry  RetryPolicy
+
+	dialect Dialect
 }
 
 func NewDatabaseSource(DBType string, URI string) (*DatabaseSource, error) {
+	dialect, err := LookupDialect(DBType)
+	if err != nil {
+		return nil, err
+	}
 	dbConn, err := sql.Open(DBType, URI)
 	if err != nil {
 		return nil, err
@@ -21,7 +30,19 @@ func NewDatabaseSource(DBType string, URI string) (*DatabaseSource, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &DatabaseSource{DBType: DBType, URI: URI, Conn: dbConn}, nil
+	return &DatabaseSource{DBType: DBType, URI: URI, Conn: dbConn, Retry: DefaultRetryPolicy(), dialect: dialect}, nil
+}
+
+// Dialect is looked up from DBType when the source was not built with
+// NewDatabaseSource, falling back to the ? placeholders of sqlite.
+func (ds *DatabaseSource) Dialect() Dialect {
+	if ds.dialect != nil {
+		return ds.dialect
+	}
+	if dialect, err := LookupDialect(ds.DBType); err == nil {
+		return dialect
+	}
+	return SQLite3
 }
 
 func (ds *DatabaseSource) Close() {
@@ -31,12 +52,67 @@ func (ds *DatabaseSource) Close() {
 }
 
 func (ds *DatabaseSource) Exec(query string, args ...any) (sql.Result, error) {
-	return Exec(ds.Conn, query, args...)
+	return ds.ExecContext(context.Background(), query, args...)
 }
 
-func (ds *DatabaseSource) ExecMany(query string, records [][]any) (error, error) {
-	return ExecMany(ds.Conn, query, records)
+func (ds *DatabaseSource) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	var res, err = ds.Conn.ExecContext(ctx, query, args...)
+	return res, translateError(ds.Dialect(), err)
 }
+
+func (ds *DatabaseSource) ExecMany(query string, records [][]any) error {
+	return ExecMany(ds, query, records)
+}
+
+func (ds *DatabaseSource) ExecManyContext(ctx context.Context, query string, records [][]any) error {
+	return ExecManyContext(ctx, ds, query, records)
+}
+
 func (ds *DatabaseSource) Query(query string, args ...any) (*sql.Rows, error) {
-	return ds.Conn.Query(query, args...)
+	return ds.QueryContext(context.Background(), query, args...)
+}
+
+func (ds *DatabaseSource) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	var rows, err = ds.Conn.QueryContext(ctx, query, args...)
+	return rows, translateError(ds.Dialect(), err)
+}
+
+func (ds *DatabaseSource) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return ds.Conn.QueryRowContext(ctx, query, args...)
+}
+
+func (ds *DatabaseSource) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return ds.Conn.PrepareContext(ctx, query)
+}
+
+func (ds *DatabaseSource) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	var tx, err = ds.Conn.BeginTx(ctx, opts)
+	return tx, translateError(ds.Dialect(), err)
+}
+
+// WithTx runs fn in a transaction, re-running the whole of fn under ds.Retry
+// on busy or serialization errors; fn must therefore be safe to repeat. Set
+// Retry to NoRetry() to run it at most once.
+func (ds *DatabaseSource) WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	return withTx(ctx, ds.Conn, ds.Dialect(), ds.Retry, fn)
+}
+
+func (ds *DatabaseSource) Tables(ctx context.Context) ([]string, error) {
+	return Tables(ctx, ds)
+}
+
+func (ds *DatabaseSource) Columns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	return Columns(ctx, ds, table)
+}
+
+func (ds *DatabaseSource) Indexes(ctx context.Context, table string) ([]IndexInfo, error) {
+	return Indexes(ctx, ds, table)
+}
+
+func (ds *DatabaseSource) ForeignKeys(ctx context.Context, table string) ([]ForeignKeyInfo, error) {
+	return ForeignKeys(ctx, ds, table)
+}
+
+func (ds *DatabaseSource) retryPolicy() RetryPolicy {
+	return ds.Retry
 }
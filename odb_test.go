@@ -1,7 +1,9 @@
 package dblite
 
 import (
+	"context"
 	"github.com/franela/goblin"
+	"slices"
 	"testing"
 	"time"
 )
@@ -42,13 +44,13 @@ func (omodel *OModel) TableName() string {
 	return "omodel"
 }
 
+const postgresURI = "user=postgres password=1234 dbname=postgres host=localhost port=5432 sslmode=disable"
+
 var dbSource *DatabaseSource
 
 func initPostgresDB() {
-	var dbType = "postgres"
-	var uri = "user=postgres password=1234 dbname=postgres host=localhost port=5432 sslmode=disable"
 	var err error
-	dbSource, err = NewDatabaseSource(dbType, uri)
+	dbSource, err = NewDatabaseSource("postgres", postgresURI)
 	checkError(err)
 	_, err = dbSource.Exec(sqlOModel)
 	checkError(err)
@@ -76,7 +78,7 @@ func TestODB(t *testing.T) {
 			on := On{
 				On: "CONFLICT(id) DO UPDATE SET email=$1, name=$2, address=$3",
 			}
-			bln, _, err := Insert(dbSource.Conn, om, cols, on, "postgres")
+			bln, _, err := Insert(dbSource, om, cols, on)
 			g.Assert(bln).IsTrue()
 			g.Assert(err).IsNil()
 		})
@@ -95,11 +97,11 @@ func TestODB(t *testing.T) {
 				On:            "CONFLICT(id)",
 				UpsertColumns: cols[1:],
 			}
-			bln, _, err := Insert(dbSource.Conn, om, cols, on, "postgres")
+			bln, _, err := Insert(dbSource, om, cols, on)
 			g.Assert(bln).IsTrue()
 			g.Assert(err).IsNil()
 
-			bln, _, err = Insert(dbSource.Conn, om, cols, on, "postgres")
+			bln, _, err = Insert(dbSource, om, cols, on)
 			g.Assert(bln).IsTrue()
 			g.Assert(err).IsNil()
 
@@ -123,9 +125,36 @@ func TestODB(t *testing.T) {
 			w := WhereClause{
 				Where: `name=?`, Arguments: []any{"model100"},
 			}
-			n, err := Delete(dbInstance.Conn, m, w)
+			n, err := Delete(dbInstance, m, w)
 			g.Assert(n).Equal(int64(1))
 			g.Assert(err).IsNil()
 		})
+
+		g.It("test introspection", func() {
+			g.Timeout(1 * time.Hour)
+			initPostgresDB()
+			defer deInitPostgresDB()
+
+			var ctx = context.Background()
+			tables, err := dbSource.Tables(ctx)
+			g.Assert(err).IsNil()
+			g.Assert(slices.Contains(tables, "omodel")).IsTrue()
+
+			columns, err := dbSource.Columns(ctx, "omodel")
+			g.Assert(err).IsNil()
+			g.Assert(len(columns)).Equal(5)
+			g.Assert(columns[0].Type).Equal("integer")
+			g.Assert(columns[0].PK).Equal(1)
+			g.Assert(columns[1].NotNull).IsTrue()
+			g.Assert(columns[2].Default.String).Equal("''::text")
+
+			indexes, err := dbSource.Indexes(ctx, "omodel")
+			g.Assert(err).IsNil()
+			g.Assert(len(indexes)).Equal(2)
+
+			keys, err := dbSource.ForeignKeys(ctx, "omodel")
+			g.Assert(err).IsNil()
+			g.Assert(len(keys)).Equal(0)
+		})
 	})
 }
@@ -0,0 +1,145 @@
+package dblite
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("dblite: invalid cursor")
+
+type cursor struct {
+	Prev bool              `json:"prev,omitempty"`
+	Keys []json.RawMessage `json:"keys"`
+
+	values []any // Keys decoded to the key fields' types
+}
+
+// Paginate returns a page of at most size models ordered by the key columns,
+// which must identify a row, using keyset pagination instead of OFFSET.
+// An empty cursor starts at the first page; the next and previous cursors
+// are empty when there is no such page.
+func Paginate[T ITable[T]](ctx context.Context, db DB, model T, keys []string, size int, token string, where ...WhereClause) ([]T, string, string, error) {
+	if len(keys) == 0 || size <= 0 {
+		return nil, "", "", errors.New("dblite: paginate requires key columns and a positive page size")
+	}
+	var c, err = decodeCursor(model.New(), keys, token)
+	if err != nil {
+		return nil, "", "", err
+	}
+	results, err := pageQuery(db.Dialect(), model, keys, size, c, where...).All(ctx, db)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	var more = len(results) > size
+	if more {
+		results = results[:size]
+	}
+	if c.Prev { // fetched backwards from the cursor
+		slices.Reverse(results)
+	}
+	if len(results) == 0 {
+		return results, "", "", nil
+	}
+
+	var hasNext, hasPrev = more, token != ""
+	if c.Prev {
+		hasNext, hasPrev = true, more
+	}
+	var next, prev string
+	if hasNext {
+		if next, err = encodeCursor(results[len(results)-1], keys, false); err != nil {
+			return nil, "", "", err
+		}
+	}
+	if hasPrev {
+		if prev, err = encodeCursor(results[0], keys, true); err != nil {
+			return nil, "", "", err
+		}
+	}
+	return results, next, prev, nil
+}
+
+// pageQuery selects size+1 rows past the cursor so a following page can be
+// detected without a count.
+func pageQuery[T ITable[T]](dialect Dialect, model T, keys []string, size int, c cursor, where ...WhereClause) *SelectQuery[T] {
+	var q = Select(model)
+	q.where = append(q.where, where...)
+
+	var op, order = ">", ""
+	if c.Prev {
+		op, order = "<", " DESC"
+	}
+	var cols = make([]string, len(keys))
+	var orderBy = make([]string, len(keys))
+	for i, key := range keys {
+		cols[i] = dialect.Quote(key)
+		orderBy[i] = cols[i] + order
+	}
+	if len(c.values) > 0 {
+		if len(keys) == 1 {
+			q.Where(fmt.Sprintf("%v %v ?", cols[0], op), c.values...)
+		} else {
+			q.Where(fmt.Sprintf("(%v) %v (%v)", strings.Join(cols, ","), op,
+				strings.TrimSuffix(strings.Repeat("?,", len(keys)), ",")), c.values...)
+		}
+	}
+	return q.OrderBy(orderBy...).Limit(size + 1)
+}
+
+func encodeCursor(model any, keys []string, prev bool) (string, error) {
+	var _, refs, err = FieldReferences(keys, model)
+	if err != nil {
+		return "", err
+	}
+	var c = cursor{Prev: prev, Keys: make([]json.RawMessage, len(refs))}
+	for i, ref := range refs {
+		if c.Keys[i], err = json.Marshal(ref); err != nil {
+			return "", err
+		}
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor unmarshals the cursor keys into the fields of model so they
+// are bound with the column's Go type.
+func decodeCursor(model any, keys []string, token string) (cursor, error) {
+	var c cursor
+	if token == "" {
+		return c, nil
+	}
+	var data, err = base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if len(c.Keys) != len(keys) {
+		return c, fmt.Errorf("%w: expects %d keys, got %d", ErrInvalidCursor, len(keys), len(c.Keys))
+	}
+	_, refs, err := FieldReferences(keys, model)
+	if err != nil {
+		return c, err
+	}
+	for i, ref := range refs {
+		if err = json.Unmarshal(c.Keys[i], ref); err != nil {
+			return c, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+	}
+	c.values = make([]any, len(refs))
+	for i, ref := range refs {
+		c.values[i] = reflect.ValueOf(ref).Elem().Interface()
+	}
+	return c, nil
+}
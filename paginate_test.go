@@ -0,0 +1,85 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+func TestPaginate(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test Paginate", func() {
+		g.It("renders keyset queries", func() {
+			var c = cursor{values: []any{"model1", int64(2)}}
+			query, args, err := pageQuery(Postgres, NewModel(-1), []string{"name", "id"}, 10, c).Columns("id").SQL(Postgres)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`SELECT "id" FROM model WHERE ("name","id") > ($1,$2) ORDER BY "name", "id" LIMIT 11;`)
+			g.Assert(args).Equal([]any{"model1", int64(2)})
+
+			c = cursor{Prev: true, values: []any{int64(5)}}
+			var where = WhereClause{Where: "active = ?", Arguments: []any{1}}
+			query, args, err = pageQuery(SQLite3, NewModel(-1), []string{"id"}, 2, c, where).Columns("id").SQL(SQLite3)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`SELECT "id" FROM model WHERE (active = ?) AND ("id" < ?) ORDER BY "id" DESC LIMIT 3;`)
+			g.Assert(args).Equal([]any{1, int64(5)})
+
+			query, _, err = pageQuery(SQLite3, NewModel(-1), []string{"id"}, 2, cursor{}).Columns("id").SQL(SQLite3)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`SELECT "id" FROM model ORDER BY "id" LIMIT 3;`)
+		})
+
+		g.It("pages forwards and backwards", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "b"},
+				{Id: 2, Email: "email2@db.com", Name: "a"},
+				{Id: 3, Email: "email3@db.com", Name: "b"},
+				{Id: 4, Email: "email4@db.com", Name: "a"},
+				{Id: 5, Email: "email5@db.com", Name: "c"},
+			}
+			g.Assert(InsertMany(dbInstance, models, []string{`id`, `email`, `name`}, On{})).IsNil()
+
+			var ctx = context.Background()
+			var keys = []string{"name", "id"}
+			var ids = func(page []*Model) []int64 {
+				return MapFn(page, func(m *Model) int64 { return m.Id })
+			}
+
+			page, next, prev, err := Paginate(ctx, dbInstance, NewModel(-1), keys, 2, "")
+			g.Assert(err).IsNil()
+			g.Assert(ids(page)).Equal([]int64{2, 4})
+			g.Assert(prev).Equal("")
+
+			page, next, prev, err = Paginate(ctx, dbInstance, NewModel(-1), keys, 2, next)
+			g.Assert(err).IsNil()
+			g.Assert(ids(page)).Equal([]int64{1, 3})
+
+			var middle = prev
+			page, next, _, err = Paginate(ctx, dbInstance, NewModel(-1), keys, 2, next)
+			g.Assert(err).IsNil()
+			g.Assert(ids(page)).Equal([]int64{5})
+			g.Assert(next).Equal("")
+
+			page, next, prev, err = Paginate(ctx, dbInstance, NewModel(-1), keys, 2, middle)
+			g.Assert(err).IsNil()
+			g.Assert(ids(page)).Equal([]int64{2, 4})
+			g.Assert(prev).Equal("")
+			g.Assert(next == "").IsFalse()
+
+			page, _, _, err = Paginate(ctx, dbInstance, NewModel(-1), []string{"id"}, 10, "",
+				WhereClause{Where: "name = ?", Arguments: []any{"b"}})
+			g.Assert(err).IsNil()
+			g.Assert(ids(page)).Equal([]int64{1, 3})
+
+			_, _, _, err = Paginate(ctx, dbInstance, NewModel(-1), keys, 2, "not a cursor")
+			g.Assert(errors.Is(err, ErrInvalidCursor)).IsTrue()
+			_, _, _, err = Paginate(ctx, dbInstance, NewModel(-1), []string{"id"}, 2, middle)
+			g.Assert(errors.Is(err, ErrInvalidCursor)).IsTrue()
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrNoPrimaryKey = errors.New("dblite: model has no primary key")
+
+// pkWhere matches the model's row by its (possibly composite) primary key and
+// reports whether the model is new, i.e. its autoincr key is still zero.
+func pkWhere(model any) (WhereClause, bool, error) {
+	var info, err = modelInfoOf(model)
+	if err != nil {
+		return WhereClause{}, false, err
+	}
+	var v = reflect.ValueOf(model).Elem()
+	var conds = make([]Cond, 0, 1)
+	var isNew = false
+	for _, f := range info.fields {
+		if !f.PK {
+			continue
+		}
+		var fv = v.FieldByIndex(f.Index)
+		isNew = isNew || f.AutoIncr && fv.IsZero()
+		conds = append(conds, Eq(f.Name, fv.Interface()))
+	}
+	if len(conds) == 0 {
+		return WhereClause{}, false, fmt.Errorf("%w: %T", ErrNoPrimaryKey, model)
+	}
+	return WhereClause{cond: And(conds...)}, isNew, nil
+}
+
+// Save inserts the model when its autoincr key is unset, otherwise it updates
+// the row by primary key, falling back to an insert if no such row exists.
+func Save[T ITable[T]](ctx context.Context, db DB, model T) error {
+	var wc, isNew, err = pkWhere(model)
+	if err != nil {
+		return err
+	}
+	if !isNew {
+		cols, err := updateColumns(model)
+		if err != nil {
+			return err
+		}
+		if len(cols) > 0 { // a model made only of key columns has nothing to update
+			updated, err := UpdateContext(ctx, db, model, cols, wc)
+			if err != nil || updated {
+				return err
+			}
+		}
+		// mysql reports 0 affected rows when the values are unchanged
+		count, err := CountContext(ctx, db, model, "*", wc)
+		if err != nil || count > 0 {
+			return err
+		}
+	}
+	_, _, err = InsertContext(ctx, db, model, nil, On{})
+	return err
+}
+
+// Reload refreshes every field of the model from its row, or returns
+// ErrNotFound.
+func Reload[T ITable[T]](ctx context.Context, db DB, model T) error {
+	var wc, _, err = pkWhere(model)
+	if err != nil {
+		return err
+	}
+	_, err = FirstContext(ctx, db, model, wc)
+	return err
+}
+
+func DeleteByPK[T ITable[T]](ctx context.Context, db DB, model T) (bool, error) {
+	var wc, _, err = pkWhere(model)
+	if err != nil {
+		return false, err
+	}
+	count, err := DeleteContext(ctx, db, model, wc)
+	return count == 1, err
+}
+
+// generatedKey returns the autoincr column whose value the database generates.
+func generatedKey(model any) (string, bool) {
+	var info, err = modelInfoOf(model)
+	if err != nil {
+		return "", false
+	}
+	for _, f := range info.fields {
+		if f.AutoIncr {
+			return f.Name, true
+		}
+	}
+	return "", false
+}
+
+// intValue reads an integer through a field reference, 0 for other kinds.
+func intValue(ref any) int64 {
+	var v = reflect.ValueOf(ref).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	}
+	return 0
+}
+
+// setAutoIncr writes a generated id into the model's autoincr integer field.
+func setAutoIncr(model any, id int64) {
+	var info, err = modelInfoOf(model)
+	if err != nil || id <= 0 {
+		return
+	}
+	var v = reflect.ValueOf(model).Elem()
+	for _, f := range info.fields {
+		if !f.AutoIncr {
+			continue
+		}
+		var fv = v.FieldByIndex(f.Index)
+		switch fv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if fv.IsZero() {
+				fv.SetInt(id)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if fv.IsZero() {
+				fv.SetUint(uint64(id))
+			}
+		}
+		return
+	}
+}
@@ -0,0 +1,190 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+const sqlMembership = `
+DROP TABLE IF EXISTS membership;
+CREATE TABLE IF NOT EXISTS membership (
+	user_id       		 INTEGER NOT NULL,
+	group_id      		 INTEGER NOT NULL,
+	role          		 TEXT DEFAULT '',
+	PRIMARY KEY (user_id, group_id)
+);
+`
+
+type Membership struct {
+	UserId  int64  `db:"user_id,pk"`
+	GroupId int64  `db:"group_id,pk"`
+	Role    string `db:"role"`
+}
+
+func (m *Membership) New() *Membership {
+	return &Membership{}
+}
+
+func (m *Membership) Clone() *Membership {
+	var o = *m
+	return &o
+}
+
+func (m *Membership) TableName() string {
+	return "membership"
+}
+
+// MembershipKey maps only the key columns of the membership table.
+type MembershipKey struct {
+	UserId  int64 `db:"user_id,pk"`
+	GroupId int64 `db:"group_id,pk"`
+}
+
+func (m *MembershipKey) New() *MembershipKey {
+	return &MembershipKey{}
+}
+
+func (m *MembershipKey) Clone() *MembershipKey {
+	var o = *m
+	return &o
+}
+
+func (m *MembershipKey) TableName() string {
+	return "membership"
+}
+
+// Tag has an integer primary key that is not tagged autoincr.
+type Tag struct {
+	Id   int64  `db:"id,pk"`
+	Name string `db:"name"`
+}
+
+func (m *Tag) New() *Tag {
+	return &Tag{}
+}
+
+func (m *Tag) Clone() *Tag {
+	var o = *m
+	return &o
+}
+
+func (m *Tag) TableName() string {
+	return "tag"
+}
+
+func TestPrimaryKey(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test primary key helpers", func() {
+		g.It("save, reload and delete by pk", func() {
+			g.Timeout(1 * time.Hour)
+			initAccountDB()
+			defer deInitDB()
+
+			var ctx = context.Background()
+			var account = &Account{Email: "email@db.com", NickName: "nick"}
+			g.Assert(Save(ctx, dbInstance, account)).IsNil()
+			g.Assert(account.Id).Equal(int64(1))
+
+			account.Email = "changed@db.com"
+			g.Assert(Save(ctx, dbInstance, account)).IsNil()
+
+			var loaded = &Account{Id: account.Id}
+			g.Assert(Reload(ctx, dbInstance, loaded)).IsNil()
+			g.Assert(loaded.Email).Equal("changed@db.com")
+			g.Assert(loaded.NickName).Equal("nick")
+
+			bln, err := DeleteByPK(ctx, dbInstance, loaded)
+			g.Assert(err).IsNil()
+			g.Assert(bln).IsTrue()
+			g.Assert(errors.Is(Reload(ctx, dbInstance, loaded), ErrNotFound)).IsTrue()
+
+			g.Assert(errors.Is(Save(ctx, dbInstance, NewModel(1)), ErrNoPrimaryKey)).IsTrue()
+		})
+
+		g.It("composite primary keys", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+			_, err := dbInstance.Exec(sqlMembership)
+			g.Assert(err).IsNil()
+
+			var ctx = context.Background()
+			var m = &Membership{UserId: 1, GroupId: 2, Role: "member"}
+			g.Assert(Save(ctx, dbInstance, m)).IsNil()
+			g.Assert(Save(ctx, dbInstance, &Membership{UserId: 1, GroupId: 3, Role: "owner"})).IsNil()
+
+			m.Role = "admin"
+			g.Assert(Save(ctx, dbInstance, m)).IsNil()
+
+			var loaded = &Membership{UserId: 1, GroupId: 2}
+			g.Assert(Reload(ctx, dbInstance, loaded)).IsNil()
+			g.Assert(loaded.Role).Equal("admin")
+
+			bln, err := DeleteByPK(ctx, dbInstance, loaded)
+			g.Assert(err).IsNil()
+			g.Assert(bln).IsTrue()
+
+			num, err := Count(dbInstance, &Membership{}, `user_id`, Compile(SQLite3, Eq("user_id", 1)))
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(1))
+		})
+
+		g.It("insert zero keys not tagged autoincr", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+			_, err := dbInstance.Exec(`DROP TABLE IF EXISTS tag; CREATE TABLE tag (id INTEGER PRIMARY KEY, name TEXT);`)
+			g.Assert(err).IsNil()
+
+			query, _, err := insertStatement(Postgres, &Tag{Name: "a"}, nil, On{}, nil)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`INSERT INTO tag("id","name") VALUES ($1,$2);`)
+
+			var tag = &Tag{Name: "a"}
+			bln, _, err := Insert(dbInstance, tag, nil, On{})
+			g.Assert(err).IsNil()
+			g.Assert(bln).IsTrue()
+			g.Assert(tag.Id).Equal(int64(0))
+
+			var loaded = &Tag{}
+			g.Assert(Reload(context.Background(), dbInstance, loaded)).IsNil()
+			g.Assert(loaded.Name).Equal("a")
+		})
+
+		g.It("save rows that report no update", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+			_, err := dbInstance.Exec(sqlMembership)
+			g.Assert(err).IsNil()
+
+			var ctx = context.Background()
+			var key = &MembershipKey{UserId: 1, GroupId: 2}
+			g.Assert(Save(ctx, dbInstance, key)).IsNil()
+			g.Assert(Save(ctx, dbInstance, key)).IsNil() // only key columns, nothing to update
+
+			// like mysql with unchanged values, the update affects no rows
+			_, err = dbInstance.Exec(`CREATE TRIGGER membership_noop BEFORE UPDATE ON membership BEGIN SELECT RAISE(IGNORE); END;`)
+			g.Assert(err).IsNil()
+			g.Assert(Save(ctx, dbInstance, &Membership{UserId: 1, GroupId: 2, Role: "member"})).IsNil()
+
+			num, err := Count(dbInstance, &Membership{}, `*`, Compile(SQLite3, Eq("user_id", 1)))
+			g.Assert(err).IsNil()
+			g.Assert(num).Equal(int64(1))
+
+			// a zero key value is a value like any other without autoincr
+			var zero = &Membership{UserId: 0, GroupId: 2, Role: "member"}
+			g.Assert(Save(ctx, dbInstance, zero)).IsNil()
+			_, err = dbInstance.Exec(`DROP TRIGGER membership_noop;`)
+			g.Assert(err).IsNil()
+			zero.Role = "admin"
+			g.Assert(Save(ctx, dbInstance, zero)).IsNil()
+			var loaded = &Membership{UserId: 0, GroupId: 2}
+			g.Assert(Reload(ctx, dbInstance, loaded)).IsNil()
+			g.Assert(loaded.Role).Equal("admin")
+		})
+	})
+}
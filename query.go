@@ -1,104 +1,197 @@
 package dblite
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
-	ref "github.com/intdxdt/goreflect"
+	"iter"
 )
 
-func Query(conn *sql.DB, query string, args ...any) (*sql.Rows, error) {
-	return conn.Query(query, args...)
+func Query(conn Executor, query string, args ...any) (*sql.Rows, error) {
+	return QueryContext(context.Background(), conn, query, args...)
 }
 
-func QueryModel[T ITable[T]](conn *sql.DB, model T, where ...WhereClause) (T, error) {
-	var fields, err = ref.Fields(model)
+func QueryContext(ctx context.Context, conn Executor, query string, args ...any) (*sql.Rows, error) {
+	var rows, err = conn.QueryContext(ctx, query, args...)
+	return rows, translateError(dialectOf(conn), err)
+}
+
+func QueryModel[T ITable[T]](db DB, model T, where ...WhereClause) (T, error) {
+	return QueryModelContext(context.Background(), db, model, where...)
+}
+
+func QueryModelContext[T ITable[T]](ctx context.Context, db DB, model T, where ...WhereClause) (T, error) {
+	var fields, err = Fields(model)
 	if err != nil {
 		return model.New(), err
 	}
-	return QueryModelByColumnNames(conn, model, fields, where...)
+	return QueryModelByColumnNamesContext(ctx, db, model, fields, where...)
+}
+
+func QueryModelByColumnNames[T ITable[T]](db DB, model T, fieldNames []string, where ...WhereClause) (T, error) {
+	return QueryModelByColumnNamesContext(context.Background(), db, model, fieldNames, where...)
 }
 
-func QueryModelByColumnNames[T ITable[T]](conn *sql.DB, model T, fieldNames []string, where ...WhereClause) (T, error) {
+func QueryModelByColumnNamesContext[T ITable[T]](ctx context.Context, db DB, model T, fieldNames []string, where ...WhereClause) (T, error) {
+	var _, err = queryModel(ctx, db, model, fieldNames, 1, where...)
+	return model, err
+}
+
+// queryModel scans the first matching row into model and returns the number
+// of rows matched, counting at most limit rows.
+func queryModel[T ITable[T]](ctx context.Context, db DB, model T, fieldNames []string, limit int, where ...WhereClause) (int, error) {
 	var tableName = model.TableName()
-	var cols, colRefs, err = ref.FilterFieldReferences(fieldNames, model)
+	var cols, colRefs, err = FieldReferences(fieldNames, model)
 	if err != nil {
-		return model, err
+		return 0, err
 	}
-	var fields = ColumnNames(cols)
+	var fields = QuotedColumnNames(cols, db.Dialect())
 
 	var args = make([]any, 0)
-	var sqlStatement = fmt.Sprintf("SELECT %v FROM %v LIMIT 1;", fields, tableName)
+	var sqlStatement = fmt.Sprintf("SELECT %v FROM %v LIMIT %d;", fields, tableName, limit)
 	if len(where) > 0 {
-		var wc = where[0]
+		wc, err := where[0].bind(db.Dialect(), 0)
+		if err != nil {
+			return 0, err
+		}
 		args = wc.Arguments
-		sqlStatement = fmt.Sprintf("SELECT %v FROM %v WHERE %v LIMIT 1;", fields, tableName, wc.Where)
+		sqlStatement = fmt.Sprintf("SELECT %v FROM %v WHERE %v LIMIT %d;", fields, tableName, wc.Where, limit)
 	}
 
-	rows, err := Query(conn, sqlStatement, args...)
+	rows, err := QueryContext(ctx, db, sqlStatement, args...)
 	if err != nil {
-		return model, err
+		return 0, err
 	}
 	defer rows.Close()
 
+	var count = 0
 	for rows.Next() {
-		err = rows.Scan(colRefs...)
-		if err != nil {
-			return model, err
+		if count == 0 {
+			err = rows.Scan(colRefs...)
+			if err != nil {
+				return count, err
+			}
+		}
+		count++
+		if count == limit {
+			break
 		}
-		break
 	}
 
 	if rows.Err() != nil {
-		return model, rows.Err()
+		return count, rows.Err()
 	}
-	return model, nil
+	return count, nil
 }
 
-func QueryModels[T ITable[T]](conn *sql.DB, model T, where ...WhereClause) ([]T, error) {
-	var fields, err = ref.Fields(model)
+func QueryModels[T ITable[T]](db DB, model T, where ...WhereClause) ([]T, error) {
+	return QueryModelsContext(context.Background(), db, model, where...)
+}
+
+func QueryModelsContext[T ITable[T]](ctx context.Context, db DB, model T, where ...WhereClause) ([]T, error) {
+	var fields, err = Fields(model)
 	if err != nil {
 		return []T{}, err
 	}
-	return QueriesByColumnNames(conn, model, fields, where...)
+	return QueriesByColumnNamesContext(ctx, db, model, fields, where...)
 }
 
-func QueriesByColumnNames[T ITable[T]](conn *sql.DB, model T, fieldNames []string, where ...WhereClause) ([]T, error) {
-	var results = make([]T, 0)
+func QueriesByColumnNames[T ITable[T]](db DB, model T, fieldNames []string, where ...WhereClause) ([]T, error) {
+	return QueriesByColumnNamesContext(context.Background(), db, model, fieldNames, where...)
+}
+
+func QueriesByColumnNamesContext[T ITable[T]](ctx context.Context, db DB, model T, fieldNames []string, where ...WhereClause) ([]T, error) {
+	var sqlStatement, colRefs, args, err = selectStatement(db.Dialect(), model, fieldNames, where...)
+	if err != nil {
+		return make([]T, 0), err
+	}
+	return queryModels(ctx, db, model, colRefs, sqlStatement, args...)
+}
+
+// QueryIter is QueryModelsContext scanning rows lazily, one per iteration.
+// Breaking out of the loop closes the underlying rows.
+func QueryIter[T ITable[T]](ctx context.Context, db DB, model T, where ...WhereClause) iter.Seq2[T, error] {
+	var fields, err = Fields(model)
+	if err != nil {
+		return errSeq(model.New(), err)
+	}
+	return QueryIterByColumnNames(ctx, db, model, fields, where...)
+}
+
+func QueryIterByColumnNames[T ITable[T]](ctx context.Context, db DB, model T, fieldNames []string, where ...WhereClause) iter.Seq2[T, error] {
+	var sqlStatement, colRefs, args, err = selectStatement(db.Dialect(), model, fieldNames, where...)
+	if err != nil {
+		return errSeq(model.New(), err)
+	}
+	return scanModels(ctx, db, model, colRefs, sqlStatement, args...)
+}
+
+func selectStatement[T ITable[T]](dialect Dialect, model T, fieldNames []string, where ...WhereClause) (string, []any, []any, error) {
 	var tableName = model.TableName()
-	var cols, colRefs, err = ref.FilterFieldReferences(fieldNames, model)
+	var cols, colRefs, err = FieldReferences(fieldNames, model)
 	if err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
-	var fields = ColumnNames(cols)
+	var fields = QuotedColumnNames(cols, dialect)
 
 	var args = make([]any, 0)
 	var sqlStatement = fmt.Sprintf("SELECT %v FROM %v;", fields, tableName)
 	if len(where) > 0 {
-		var wc = where[0]
+		wc, err := where[0].bind(dialect, 0)
+		if err != nil {
+			return "", nil, nil, err
+		}
 		args = wc.Arguments
-		if len(args) == 0 {
-			return results, errors.New("invalid number arguments in where clause")
+		if len(args) == 0 && where[0].cond == nil {
+			return "", nil, nil, errors.New("invalid number arguments in where clause")
 		}
 		sqlStatement = fmt.Sprintf("SELECT %v FROM %v WHERE %v;", fields, tableName, wc.Where)
 	}
+	return sqlStatement, colRefs, args, nil
+}
 
-	rows, err := Query(conn, sqlStatement, args...)
-	if err != nil {
-		return results, err
+// queryModels scans every row of the query into model through colRefs and
+// collects a clone of the model per row.
+func queryModels[T ITable[T]](ctx context.Context, db DB, model T, colRefs []any, query string, args ...any) ([]T, error) {
+	var results = make([]T, 0)
+	for m, err := range scanModels(ctx, db, model, colRefs, query, args...) {
+		if err != nil {
+			return results, err
+		}
+		results = append(results, m)
 	}
-	defer rows.Close()
+	return results, nil
+}
 
-	for rows.Next() {
-		err = rows.Scan(colRefs...)
+// scanModels yields a clone of model per row of the query, stopping after the
+// first error.
+func scanModels[T ITable[T]](ctx context.Context, db DB, model T, colRefs []any, query string, args ...any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		rows, err := QueryContext(ctx, db, query, args...)
 		if err != nil {
-			return results, err
+			yield(model.New(), err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			if err = rows.Scan(colRefs...); err != nil {
+				yield(model.New(), err)
+				return
+			}
+			if !yield(model.Clone(), nil) {
+				return
+			}
+		}
+		if rows.Err() != nil {
+			yield(model.New(), rows.Err())
 		}
-		results = append(results, model.Clone())
 	}
+}
 
-	if rows.Err() != nil {
-		return results, rows.Err()
+func errSeq[T any](zero T, err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		yield(zero, err)
 	}
-	return results, nil
 }
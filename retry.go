@@ -0,0 +1,86 @@
+package dblite
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Retryable   func(err error) bool // defaults to the dialect classifier
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   50 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+	}
+}
+
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// Do runs fn until it succeeds, fails with a non retryable error or the
+// attempts are exhausted, sleeping with exponential backoff and jitter
+// between attempts. A policy without MaxAttempts, such as the zero value,
+// retries like DefaultRetryPolicy.
+func (policy RetryPolicy) Do(ctx context.Context, dialect Dialect, fn func() error) error {
+	if policy.MaxAttempts == 0 {
+		var retryable = policy.Retryable
+		policy = DefaultRetryPolicy()
+		policy.Retryable = retryable
+	}
+	var retryable = policy.Retryable
+	if retryable == nil && dialect != nil {
+		retryable = dialect.IsRetryable
+	}
+	if retryable == nil {
+		retryable = func(error) bool { return false }
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt >= policy.MaxAttempts || !retryable(err) {
+			return err
+		}
+
+		var timer = time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+func (policy RetryPolicy) backoff(attempt int) time.Duration {
+	var delay = policy.BaseDelay << (attempt - 1)
+	if delay <= 0 || (policy.MaxDelay > 0 && delay > policy.MaxDelay) {
+		delay = policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	// full jitter over the upper half of the window
+	return delay/2 + rand.N(delay/2+1)
+}
+
+type retrier interface {
+	retryPolicy() RetryPolicy
+}
+
+func retryPolicyOf(conn Executor) RetryPolicy {
+	if r, ok := conn.(retrier); ok {
+		return r.retryPolicy()
+	}
+	return NoRetry()
+}
@@ -0,0 +1,64 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"github.com/franela/goblin"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test RetryPolicy", func() {
+		g.It("retries busy errors until success", func() {
+			var policy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+			var attempts = 0
+			err := policy.Do(context.Background(), SQLite3, func() error {
+				attempts++
+				if attempts < 3 {
+					return sqlite3.Error{Code: sqlite3.ErrBusy}
+				}
+				return nil
+			})
+			g.Assert(err).IsNil()
+			g.Assert(attempts).Equal(3)
+		})
+
+		g.It("stops on non retryable errors", func() {
+			var policy = RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond}
+			var attempts = 0
+			var errFatal = errors.New("fatal")
+			err := policy.Do(context.Background(), Postgres, func() error {
+				attempts++
+				return errFatal
+			})
+			g.Assert(errors.Is(err, errFatal)).IsTrue()
+			g.Assert(attempts).Equal(1)
+		})
+
+		g.It("treats a zero policy as the default", func() {
+			var db = &Database{dialect: SQLite3} // built without NewDatabase
+			var attempts = 0
+			err := db.retryPolicy().Do(context.Background(), SQLite3, func() error {
+				attempts++
+				if attempts < 2 {
+					return sqlite3.Error{Code: sqlite3.ErrBusy}
+				}
+				return nil
+			})
+			g.Assert(err).IsNil()
+			g.Assert(attempts).Equal(2)
+			g.Assert(DefaultRetryPolicy().MaxAttempts).Equal(5)
+		})
+
+		g.It("classifies dialect errors", func() {
+			g.Assert(SQLite3.IsRetryable(sqlite3.Error{Code: sqlite3.ErrLocked})).IsTrue()
+			g.Assert(SQLite3.IsRetryable(sqlite3.Error{Code: sqlite3.ErrConstraint})).IsFalse()
+			g.Assert(Postgres.IsRetryable(&pq.Error{Code: "40P01"})).IsTrue()
+			g.Assert(Postgres.IsRetryable(&pq.Error{Code: "23505"})).IsFalse()
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+func TestReturning(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test RETURNING", func() {
+		g.It("scans returned columns into models", func() {
+			g.Timeout(1 * time.Hour)
+			initAccountDB()
+			defer deInitDB()
+
+			var ctx = context.Background()
+			var account = &Account{Email: "email1@db.com"}
+			bln, err := InsertReturningContext(ctx, dbInstance, account, nil, On{}, []string{"id", "nick_name", "created_at"})
+			g.Assert(err).IsNil()
+			g.Assert(bln).IsTrue()
+			g.Assert(account.Id).Equal(int64(1))
+			g.Assert(account.NickName).Equal("anon")
+			g.Assert(account.Created == "").IsFalse()
+
+			g.Assert(Save(ctx, dbInstance, &Account{Email: "email2@db.com"})).IsNil()
+
+			var update = &Account{NickName: "renamed"}
+			results, err := UpdateReturning(dbInstance, update, []string{"nick_name"},
+				WhereClause{Where: "id > ?", Arguments: []any{0}}, []string{"id", "email"})
+			g.Assert(err).IsNil()
+			g.Assert(len(results)).Equal(2)
+			g.Assert(results[0].Email).Equal("email1@db.com")
+			g.Assert(results[1].Email).Equal("email2@db.com")
+			g.Assert(results[0].NickName).Equal("") // not returned
+			g.Assert(*update).Equal(Account{NickName: "renamed"})
+
+			results, err = DeleteReturningContext(ctx, dbInstance, &Account{}, Compile(SQLite3, Eq("id", 2)), []string{"id", "nick_name"})
+			g.Assert(err).IsNil()
+			g.Assert(len(results)).Equal(1)
+			g.Assert(results[0].NickName).Equal("renamed")
+
+			_, err = DeleteReturning(Bind(dbInstance.Conn, MySQL), &Account{}, Compile(MySQL, Eq("id", 1)), []string{"id"})
+			g.Assert(errors.Is(err, ErrReturningNotSupported)).IsTrue()
+		})
+	})
+}
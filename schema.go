@@ -0,0 +1,131 @@
+package dblite
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType  = reflect.TypeFor[time.Time]()
+	bytesType = reflect.TypeFor[[]byte]()
+)
+
+// storedType unwraps pointers and the database/sql Null types to the type
+// of the stored value.
+func storedType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t.PkgPath() == "database/sql" && strings.HasPrefix(t.Name(), "Null") {
+		return storedType(t.Field(0).Type) // sql.NullString.String, sql.Null[T].V
+	}
+	return t
+}
+
+type tableIndex struct {
+	name   string
+	unique bool
+	cols   []string
+}
+
+// CreateTableSQL renders the CREATE TABLE statement of the model, followed by
+// its CREATE INDEX statements on sqlite and postgres. Column types are derived
+// from the field types unless set with the type= tag option.
+func CreateTableSQL[T ITable[T]](model T, dialect Dialect) (string, error) {
+	var info, err = modelInfoOf(model)
+	if err != nil {
+		return "", err
+	}
+	var table = model.TableName()
+	keys, err := PrimaryKey(model)
+	if err != nil {
+		return "", err
+	}
+
+	var defs = make([]string, 0, len(info.fields)+1)
+	var indexes = make([]*tableIndex, 0)
+	var byName = make(map[string]*tableIndex)
+	for _, f := range info.fields {
+		var def, err = columnDefinition(dialect, f, len(keys) == 1)
+		if err != nil {
+			return "", err
+		}
+		defs = append(defs, def)
+
+		if f.IndexName == "" {
+			continue
+		}
+		var idx, ok = byName[f.IndexName]
+		if !ok {
+			var suffix = "_idx"
+			if f.UniqueIndex {
+				suffix = "_uniq"
+			}
+			idx = &tableIndex{name: strings.ReplaceAll(table, ".", "_") + "_" + f.IndexName + suffix, unique: f.UniqueIndex}
+			byName[f.IndexName] = idx
+			indexes = append(indexes, idx)
+		}
+		idx.cols = append(idx.cols, f.Name)
+	}
+	if len(keys) > 1 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%v)", QuotedColumnNames(keys, dialect)))
+	}
+
+	var inline = !dialect.SupportsCreateIndexIfNotExists()
+	var sb strings.Builder
+	for _, idx := range indexes {
+		var unique = ""
+		if idx.unique {
+			unique = "UNIQUE "
+		}
+		if inline {
+			defs = append(defs, fmt.Sprintf("%vINDEX %v (%v)", unique, dialect.Quote(idx.name), QuotedColumnNames(idx.cols, dialect)))
+			continue
+		}
+		fmt.Fprintf(&sb, "\nCREATE %vINDEX IF NOT EXISTS %v ON %v (%v);",
+			unique, dialect.Quote(idx.name), table, QuotedColumnNames(idx.cols, dialect))
+	}
+
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %v (\n\t%v\n);", table, strings.Join(defs, ",\n\t")) + sb.String(), nil
+}
+
+func columnDefinition(dialect Dialect, f FieldInfo, singleKey bool) (string, error) {
+	var colType = f.SQLType
+	if colType == "" {
+		colType = dialect.ColumnType(f)
+	}
+	if colType == "" {
+		return "", fmt.Errorf("column '%s': no SQL type for %v, set one with the type= tag option", f.Name, f.Type)
+	}
+
+	var def = []string{dialect.Quote(f.Name), colType}
+	if f.NotNull {
+		def = append(def, "NOT NULL")
+	}
+	if f.Default != "" {
+		def = append(def, "DEFAULT "+f.Default)
+	}
+	if f.Unique {
+		def = append(def, "UNIQUE")
+	}
+	if f.PK && singleKey {
+		def = append(def, "PRIMARY KEY")
+	}
+	if f.AutoIncr {
+		def = append(def, dialect.AutoIncrement())
+	}
+	return strings.Join(def, " "), nil
+}
+
+// CreateTable creates the model's table and indexes if they do not exist.
+func CreateTable[T ITable[T]](ctx context.Context, db DB, model T) error {
+	var sqlStatement, err = CreateTableSQL(model, db.Dialect())
+	if err != nil {
+		return err
+	}
+	_, err = ExecContext(ctx, db, sqlStatement)
+	return err
+}
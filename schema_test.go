@@ -0,0 +1,135 @@
+package dblite
+
+import (
+	"context"
+	"database/sql"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+type Event struct {
+	Tenant  string          `db:"tenant,pk"`
+	Id      int64           `db:"id,pk"`
+	Kind    string          `db:"kind,notnull,index=kind_at"`
+	At      time.Time       `db:"at,notnull,index=kind_at"`
+	Done    bool            `db:"done,default=false"`
+	Payload []byte          `db:"payload"`
+	Note    sql.NullString  `db:"note"`
+	Score   sql.Null[int32] `db:"score"`
+	Ref     *string         `db:"ref,uniqueindex"`
+	Code    string          `db:"code,type=CHAR(3)"`
+}
+
+func (event *Event) New() *Event {
+	return &Event{}
+}
+
+func (event *Event) Clone() *Event {
+	var o = *event
+	return &o
+}
+
+func (event *Event) TableName() string {
+	return "event"
+}
+
+type Unmapped struct {
+	Id   int64          `db:"id,pk"`
+	Tags map[string]int `db:"tags"`
+}
+
+func (u *Unmapped) New() *Unmapped {
+	return &Unmapped{}
+}
+
+func (u *Unmapped) Clone() *Unmapped {
+	var o = *u
+	return &o
+}
+
+func (u *Unmapped) TableName() string {
+	return "unmapped"
+}
+
+func TestSchema(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test Schema", func() {
+		g.It("renders create table statements", func() {
+			query, err := CreateTableSQL(&Account{}, SQLite3)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`CREATE TABLE IF NOT EXISTS account (
+	"id" INTEGER PRIMARY KEY AUTOINCREMENT,
+	"email" TEXT NOT NULL UNIQUE,
+	"nick_name" TEXT DEFAULT 'anon',
+	"created_at" TEXT DEFAULT CURRENT_TIMESTAMP
+);`)
+
+			query, err = CreateTableSQL(&Event{}, Postgres)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`CREATE TABLE IF NOT EXISTS event (
+	"tenant" TEXT,
+	"id" BIGINT,
+	"kind" TEXT NOT NULL,
+	"at" TIMESTAMP NOT NULL,
+	"done" BOOLEAN DEFAULT false,
+	"payload" BYTEA,
+	"note" TEXT,
+	"score" INTEGER,
+	"ref" TEXT,
+	"code" CHAR(3),
+	PRIMARY KEY ("tenant","id")
+);
+CREATE INDEX IF NOT EXISTS "event_kind_at_idx" ON event ("kind","at");
+CREATE UNIQUE INDEX IF NOT EXISTS "event_ref_uniq" ON event ("ref");`)
+
+			query, err = CreateTableSQL(&Event{}, MySQL)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal("CREATE TABLE IF NOT EXISTS event (\n" +
+				"\t`tenant` VARCHAR(255),\n" +
+				"\t`id` BIGINT,\n" +
+				"\t`kind` VARCHAR(255) NOT NULL,\n" +
+				"\t`at` DATETIME NOT NULL,\n" +
+				"\t`done` BOOLEAN DEFAULT false,\n" +
+				"\t`payload` BLOB,\n" +
+				"\t`note` TEXT,\n" +
+				"\t`score` INT,\n" +
+				"\t`ref` VARCHAR(255),\n" +
+				"\t`code` CHAR(3),\n" +
+				"\tPRIMARY KEY (`tenant`,`id`),\n" +
+				"\tINDEX `event_kind_at_idx` (`kind`,`at`),\n" +
+				"\tUNIQUE INDEX `event_ref_uniq` (`ref`)\n" +
+				");")
+
+			_, err = CreateTableSQL(&Unmapped{}, SQLite3)
+			g.Assert(err == nil).IsFalse()
+		})
+
+		g.It("creates tables", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			_, err := dbInstance.Exec(`DROP TABLE IF EXISTS event;`)
+			g.Assert(err).IsNil()
+
+			var ctx = context.Background()
+			g.Assert(CreateTable(ctx, dbInstance, &Event{})).IsNil()
+			g.Assert(CreateTable(ctx, dbInstance, &Event{})).IsNil() // idempotent
+
+			var ref = "r1"
+			var event = &Event{Tenant: "t", Id: 1, Kind: "k", At: time.Now().UTC(), Ref: &ref, Code: "abc"}
+			_, _, err = Insert(dbInstance, event, nil, On{})
+			g.Assert(err).IsNil()
+
+			event.Id = 2
+			_, _, err = Insert(dbInstance, event, nil, On{})
+			g.Assert(err == nil).IsFalse() // unique index on ref
+
+			var count int
+			err = dbInstance.Conn.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type='index' AND tbl_name='event' AND name LIKE 'event_%'`).Scan(&count)
+			g.Assert(err).IsNil()
+			g.Assert(count).Equal(2)
+		})
+	})
+}
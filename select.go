@@ -0,0 +1,162 @@
+package dblite
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+type SelectQuery[T ITable[T]] struct {
+	model   T
+	columns []string
+	where   []WhereClause
+	orderBy []string
+	limit   int
+	offset  int
+}
+
+// Select starts a query over the model's table. Where fragments use ?
+// placeholders on every dialect; they are renumbered when rendered.
+func Select[T ITable[T]](model T) *SelectQuery[T] {
+	return &SelectQuery[T]{model: model, limit: -1, offset: -1}
+}
+
+func (q *SelectQuery[T]) Columns(cols ...string) *SelectQuery[T] {
+	q.columns = append(q.columns, cols...)
+	return q
+}
+
+// Where adds a condition, multiple conditions are combined with AND.
+func (q *SelectQuery[T]) Where(where string, args ...any) *SelectQuery[T] {
+	q.where = append(q.where, WhereClause{Where: where, Arguments: args})
+	return q
+}
+
+func (q *SelectQuery[T]) WhereNamed(where string, named any) *SelectQuery[T] {
+	q.where = append(q.where, WhereClause{Where: where, Named: named})
+	return q
+}
+
+func (q *SelectQuery[T]) WhereCond(cond Cond) *SelectQuery[T] {
+	q.where = append(q.where, WhereClause{cond: cond})
+	return q
+}
+
+func (q *SelectQuery[T]) OrderBy(cols ...string) *SelectQuery[T] {
+	q.orderBy = append(q.orderBy, cols...)
+	return q
+}
+
+func (q *SelectQuery[T]) Limit(n int) *SelectQuery[T] {
+	q.limit = n
+	return q
+}
+
+func (q *SelectQuery[T]) Offset(n int) *SelectQuery[T] {
+	q.offset = n
+	return q
+}
+
+func (q *SelectQuery[T]) fields() ([]string, error) {
+	if len(q.columns) > 0 {
+		return q.columns, nil
+	}
+	return Fields(q.model)
+}
+
+func (q *SelectQuery[T]) SQL(dialect Dialect) (string, []any, error) {
+	var fields, err = q.fields()
+	if err != nil {
+		return "", nil, err
+	}
+	cols, _, err := FieldReferences(fields, q.model)
+	if err != nil {
+		return "", nil, err
+	}
+	return q.render(dialect, cols)
+}
+
+func (q *SelectQuery[T]) render(dialect Dialect, cols []string) (string, []any, error) {
+	var sb strings.Builder
+	var args = make([]any, 0)
+	fmt.Fprintf(&sb, "SELECT %v FROM %v", QuotedColumnNames(cols, dialect), q.model.TableName())
+
+	if len(q.where) > 0 {
+		var conds = make([]string, len(q.where))
+		for i, wc := range q.where {
+			var bound, err = wc.bind(dialect, len(args))
+			if err != nil {
+				return "", nil, err
+			}
+			conds[i] = bound.Where
+			args = append(args, bound.Arguments...)
+		}
+		if len(conds) == 1 {
+			fmt.Fprintf(&sb, " WHERE %v", conds[0])
+		} else {
+			fmt.Fprintf(&sb, " WHERE (%v)", strings.Join(conds, ") AND ("))
+		}
+	}
+	if len(q.orderBy) > 0 {
+		fmt.Fprintf(&sb, " ORDER BY %v", strings.Join(q.orderBy, ", "))
+	}
+	if q.limit >= 0 {
+		fmt.Fprintf(&sb, " LIMIT %d", q.limit)
+	}
+	if q.offset >= 0 {
+		if noLimit := dialect.NoLimit(); q.limit < 0 && noLimit != "" {
+			sb.WriteString(" " + noLimit)
+		}
+		fmt.Fprintf(&sb, " OFFSET %d", q.offset)
+	}
+	sb.WriteString(";")
+	return sb.String(), args, nil
+}
+
+func (q *SelectQuery[T]) All(ctx context.Context, db DB) ([]T, error) {
+	var fields, err = q.fields()
+	if err != nil {
+		return nil, err
+	}
+	cols, colRefs, err := FieldReferences(fields, q.model)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := q.render(db.Dialect(), cols)
+	if err != nil {
+		return nil, err
+	}
+	return queryModels(ctx, db, q.model, colRefs, query, args...)
+}
+
+// Iter is All scanning rows lazily, one per iteration.
+func (q *SelectQuery[T]) Iter(ctx context.Context, db DB) iter.Seq2[T, error] {
+	var fields, err = q.fields()
+	if err != nil {
+		return errSeq(q.model.New(), err)
+	}
+	cols, colRefs, err := FieldReferences(fields, q.model)
+	if err != nil {
+		return errSeq(q.model.New(), err)
+	}
+	query, args, err := q.render(db.Dialect(), cols)
+	if err != nil {
+		return errSeq(q.model.New(), err)
+	}
+	return scanModels(ctx, db, q.model, colRefs, query, args...)
+}
+
+// First returns the first matching row, or ErrNotFound.
+func (q *SelectQuery[T]) First(ctx context.Context, db DB) (T, error) {
+	var c = *q // leave the shared query untouched
+	c.limit = 1
+	var results, err = c.All(ctx, db)
+	if err != nil {
+		return q.model, err
+	}
+	if len(results) == 0 {
+		return q.model, ErrNotFound
+	}
+	return results[0], nil
+}
@@ -0,0 +1,68 @@
+package dblite
+
+import (
+	"context"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+func TestSelect(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test Select", func() {
+		g.It("renders dialect placeholders", func() {
+			var q = Select(NewModel(-1)).
+				Columns("id", "name").
+				Where("name = ? AND address <> '?'", "model1").
+				Where("active = ?", 1).
+				OrderBy("id DESC").
+				Limit(10).
+				Offset(20)
+
+			query, args, err := q.SQL(Postgres)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`SELECT "id","name" FROM model WHERE (name = $1 AND address <> '?') AND (active = $2) ORDER BY id DESC LIMIT 10 OFFSET 20;`)
+			g.Assert(args).Equal([]any{"model1", 1})
+
+			query, _, err = Select(NewModel(-1)).Columns("id").Offset(5).SQL(SQLite3)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`SELECT "id" FROM model LIMIT -1 OFFSET 5;`)
+		})
+
+		g.It("scans into models", func() {
+			g.Timeout(1 * time.Hour)
+			initDB()
+			defer deInitDB()
+
+			var models = []*Model{
+				{Id: 1, Email: "email1@db.com", Name: "model1"},
+				{Id: 2, Email: "email2@db.com", Name: "model1"},
+				{Id: 3, Email: "email3@db.com", Name: "model3"},
+			}
+			g.Assert(InsertMany(dbInstance, models, []string{`id`, `email`, `name`}, On{})).IsNil()
+
+			var ctx = context.Background()
+			results, err := Select(NewModel(-1)).Where("name = ?", "model1").OrderBy("id DESC").All(ctx, dbInstance)
+			g.Assert(err).IsNil()
+			g.Assert(len(results)).Equal(2)
+			g.Assert(results[0].Id).Equal(int64(2))
+			g.Assert(results[1].Email).Equal("email1@db.com")
+
+			results, err = Select(NewModel(-1)).OrderBy("id").Limit(1).Offset(2).All(ctx, dbInstance)
+			g.Assert(err).IsNil()
+			g.Assert(len(results)).Equal(1)
+			g.Assert(results[0].Id).Equal(int64(3))
+
+			_, err = Select(NewModel(-1)).Where("id = ?", 42).First(ctx, dbInstance)
+			g.Assert(err).Equal(ErrNotFound)
+
+			var q = Select(NewModel(-1)).OrderBy("id").Limit(3)
+			first, err := q.First(ctx, dbInstance)
+			g.Assert(err).IsNil()
+			g.Assert(first.Id).Equal(int64(1))
+			query, _, err := q.SQL(SQLite3)
+			g.Assert(err).IsNil()
+			g.Assert(query).Equal(`SELECT "id","email","name","address","active" FROM model ORDER BY id LIMIT 3;`)
+		})
+	})
+}
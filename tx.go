@@ -0,0 +1,148 @@
+package dblite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+type Tx struct {
+	*sql.Tx
+	dialect Dialect
+	depth   int
+}
+
+func (tx *Tx) Dialect() Dialect {
+	return tx.dialect
+}
+
+// WithTx runs fn inside a savepoint of the enclosing transaction: a failing
+// fn only rolls back its own work and leaves the outer transaction usable.
+func (tx *Tx) WithTx(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	var nested = &Tx{Tx: tx.Tx, dialect: tx.dialect, depth: tx.depth + 1}
+	var savepoint = fmt.Sprintf("sp_%d", nested.depth)
+
+	if _, err = tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return err
+	}
+
+	var rollback = func() error {
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(nested); err != nil {
+		if errRollback := rollback(); errRollback != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, errRollback)
+		}
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+	return err
+}
+
+type boundExecutor struct {
+	Executor
+	dialect Dialect
+}
+
+func (b *boundExecutor) Dialect() Dialect {
+	return b.dialect
+}
+
+// Bind pairs a raw *sql.DB, *sql.Tx or *sql.Conn with a dialect so that it
+// can be passed to the generic CRUD functions.
+func Bind(conn Executor, dialect Dialect) DB {
+	return &boundExecutor{Executor: conn, dialect: dialect}
+}
+
+// WithTx starts a transaction on db, or a savepoint when db is already a
+// transaction, so code built on top of dblite can nest units of work. A new
+// transaction is re-run under db's retry policy on busy or serialization
+// errors, so fn must be safe to run more than once; savepoints are not
+// retried.
+func WithTx(ctx context.Context, db DB, fn func(tx *Tx) error) error {
+	return withTxPolicy(ctx, db, retryPolicyOf(db), fn)
+}
+
+func withTxPolicy(ctx context.Context, db DB, policy RetryPolicy, fn func(tx *Tx) error) error {
+	if tx, ok := db.(*Tx); ok {
+		return tx.WithTx(ctx, fn)
+	}
+	if b, ok := db.(*boundExecutor); ok {
+		if sqlTx, ok := b.Executor.(*sql.Tx); ok {
+			return (&Tx{Tx: sqlTx, dialect: b.dialect}).WithTx(ctx, fn)
+		}
+	}
+	if beginner, ok := beginnerOf(db); ok {
+		return withTx(ctx, beginner, db.Dialect(), policy, fn)
+	}
+	return fmt.Errorf("dblite: %T cannot start a transaction", db)
+}
+
+func beginnerOf(conn Executor) (txBeginner, bool) {
+	if b, ok := conn.(*boundExecutor); ok {
+		conn = b.Executor
+	}
+	var beginner, ok = conn.(txBeginner)
+	return beginner, ok
+}
+
+func dialectOf(conn Executor) Dialect {
+	if db, ok := conn.(DB); ok {
+		return db.Dialect()
+	}
+	return nil
+}
+
+// withTx retries the whole transaction on retryable errors, so fn must be
+// safe to run more than once.
+func withTx(ctx context.Context, conn txBeginner, dialect Dialect, policy RetryPolicy, fn func(tx *Tx) error) error {
+	return policy.Do(ctx, dialect, func() error {
+		return runTx(ctx, conn, dialect, fn)
+	})
+}
+
+func runTx(ctx context.Context, conn txBeginner, dialect Dialect, fn func(tx *Tx) error) (err error) {
+	sqlTx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return translateError(dialect, err)
+	}
+	var tx = &Tx{Tx: sqlTx, dialect: dialect}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = sqlTx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		if errRollback := sqlTx.Rollback(); errRollback != nil {
+			return fmt.Errorf("%w (rollback: %v)", err, errRollback)
+		}
+		return err
+	}
+	return translateError(dialect, sqlTx.Commit())
+}
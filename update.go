@@ -1,22 +1,74 @@
 package dblite
 
 import (
-	"database/sql"
+	"context"
 	"fmt"
-	ref "github.com/intdxdt/goreflect"
 )
 
-func Update[T ITable[T]](conn *sql.DB, model T, updateCols []string, wc WhereClause, dbType string) (bool, error) {
-	var fields, err = ref.Fields(model)
+func Update[T ITable[T]](db DB, model T, updateCols []string, wc WhereClause) (bool, error) {
+	return UpdateContext(context.Background(), db, model, updateCols, wc)
+}
+
+func UpdateContext[T ITable[T]](ctx context.Context, db DB, model T, updateCols []string, wc WhereClause) (bool, error) {
+	var query, values, err = updateStatement(db.Dialect(), model, updateCols, wc, nil)
 	if err != nil {
 		return false, err
 	}
 
-	fields, colRefs, err := ref.FilterFieldReferences(fields, model)
+	res, err := ExecContext(ctx, db, query, values...)
+
 	if err != nil {
 		return false, err
 	}
 
+	count, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count == 1, nil
+}
+
+// UpdateReturning updates the matching rows and returns a new model per row
+// holding only the returning columns; the model itself is left untouched.
+func UpdateReturning[T ITable[T]](db DB, model T, updateCols []string, wc WhereClause, returning []string) ([]T, error) {
+	return UpdateReturningContext(context.Background(), db, model, updateCols, wc, returning)
+}
+
+func UpdateReturningContext[T ITable[T]](ctx context.Context, db DB, model T, updateCols []string, wc WhereClause, returning []string) ([]T, error) {
+	var dialect = db.Dialect()
+	if err := checkReturning(dialect); err != nil {
+		return nil, err
+	}
+	var query, values, err = updateStatement(dialect, model, updateCols, wc, returning)
+	if err != nil {
+		return nil, err
+	}
+	var row = model.New()
+	_, refs, err := FieldReferences(returning, row)
+	if err != nil {
+		return nil, err
+	}
+	return queryModels(ctx, db, row, refs, query, values...)
+}
+
+func updateStatement[T ITable[T]](dialect Dialect, model T, updateCols []string, wc WhereClause, returning []string) (string, []any, error) {
+	var fields, err = Fields(model)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(updateCols) == 0 {
+		updateCols, err = updateColumns(model)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	fields, colRefs, err := FieldReferences(fields, model)
+	if err != nil {
+		return "", nil, err
+	}
+
 	var cols = make([]string, 0, len(fields))
 	var values = make([]any, 0, len(fields))
 
@@ -29,25 +81,18 @@ func Update[T ITable[T]](conn *sql.DB, model T, updateCols []string, wc WhereCla
 		}
 	}
 
-	var holders = UpdatePlaceholders(cols, dbType)
+	var holders = UpdatePlaceholders(cols, dialect)
+	wc, err = wc.bind(dialect, len(cols))
+	if err != nil {
+		return "", nil, err
+	}
 	for _, arg := range wc.Arguments {
 		values = append(values, arg)
 	}
 
 	var query = fmt.Sprintf(
-		`UPDATE %v SET %v WHERE %v;`,
-		model.TableName(), holders, wc.Where)
-
-	res, err := Exec(conn, query, values...)
+		`UPDATE %v SET %v WHERE %v%v;`,
+		model.TableName(), holders, wc.Where, returningClause(dialect, returning))
 
-	if err != nil {
-		return false, err
-	}
-
-	count, err := res.RowsAffected()
-	if err != nil {
-		return false, err
-	}
-
-	return count == 1, nil
+	return query, values, nil
 }
@@ -2,7 +2,6 @@ package dblite
 
 import (
 	"fmt"
-	ref "github.com/intdxdt/goreflect"
 	"regexp"
 	"strings"
 )
@@ -65,44 +64,45 @@ func ColumnNames(cols []string) string {
 	return strings.Join(cols, ",")
 }
 
-func ColumnEqualPlaceholders(cols []string, dbType string) string {
+// QuotedColumnNames is ColumnNames with each column quoted for the dialect.
+func QuotedColumnNames(cols []string, dialect Dialect) string {
+	return strings.Join(MapFn(cols, dialect.Quote), ",")
+}
+
+func ColumnEqualPlaceholders(cols []string, dialect Dialect) string {
 	var columns = make([]string, len(cols))
 	for i, col := range cols {
-		switch dbType {
-		case "postgres":
-			columns[i] = fmt.Sprintf("%s = $%d", col, i+1)
-		default:
-			columns[i] = fmt.Sprintf("%s = ?", col)
-		}
+		columns[i] = fmt.Sprintf("%s = %s", dialect.Quote(col), dialect.Placeholder(i+1))
 	}
 	return strings.Join(columns, ", ")
 }
 
-func ColumnEqualParamAttributes(cols []string, dbType string) string {
-	startIndex := len(cols) + 2
+func ColumnEqualParamAttributes(cols []string, dialect Dialect, offset int) string {
 	updates := make([]string, len(cols))
 	for i, col := range cols {
-		switch dbType {
-		case "postgres":
-			updates[i] = fmt.Sprintf("%s = $%d", col, startIndex+i)
-		default:
-			updates[i] = fmt.Sprintf("%s = ?", col)
-		}
+		updates[i] = fmt.Sprintf("%s = %s", dialect.Quote(col), dialect.Placeholder(offset+i+1))
 	}
 	return strings.Join(updates, ", ")
 }
 
-func ColumnPlaceholders(cols []string, dbType string) string {
-	switch dbType {
-	case "postgres":
-		placeholders := make([]string, len(cols))
-		for i := range cols {
-			placeholders[i] = fmt.Sprintf("$%d", i+1)
-		}
-		return strings.Join(placeholders, ",")
-	default:
-		return strings.TrimRight(strings.Repeat("?,", len(cols)), ",")
+func ColumnEqualExcluded(cols []string, dialect Dialect) string {
+	var updates = make([]string, len(cols))
+	for i, col := range cols {
+		updates[i] = fmt.Sprintf("%s = %s", dialect.Quote(col), dialect.Excluded(col))
 	}
+	return strings.Join(updates, ", ")
+}
+
+func ColumnPlaceholders(cols []string, dialect Dialect) string {
+	return placeholders(dialect, len(cols), 0)
+}
+
+func placeholders(dialect Dialect, n int, offset int) string {
+	var holders = make([]string, n)
+	for i := range holders {
+		holders[i] = dialect.Placeholder(offset + i + 1)
+	}
+	return strings.Join(holders, ",")
 }
 
 func MapFnWithIndex[T any](in []T, fn func(int, T) string) []string {
@@ -113,24 +113,19 @@ func MapFnWithIndex[T any](in []T, fn func(int, T) string) []string {
 	return out
 }
 
-func UpdatePlaceholders(cols []string, dbType string) string {
+func UpdatePlaceholders(cols []string, dialect Dialect) string {
 	return strings.Join(MapFnWithIndex(cols, func(i int, col string) string {
-		switch dbType {
-		case "postgres":
-			return fmt.Sprintf(`%v=$%d`, col, i+1)
-		default: // defaults to sqlite3
-			return fmt.Sprintf(`%v=?`, col)
-		}
+		return fmt.Sprintf(`%v=%v`, dialect.Quote(col), dialect.Placeholder(i+1))
 	}), `,`)
 }
 
 func ColumnsByExclusion[T ITable[T]](model T, excludeColumns []string) ([]string, error) {
-	var fields, err = ref.Fields(model)
+	var fields, err = Fields(model)
 	if err != nil {
 		return nil, err
 	}
 
-	fields, _, err = ref.FilterFieldReferences(fields, model)
+	fields, _, err = FieldReferences(fields, model)
 	if err != nil {
 		return nil, err
 	}
@@ -146,6 +141,55 @@ func ColumnsByExclusion[T ITable[T]](model T, excludeColumns []string) ([]string
 	return cols, nil
 }
 
+// rebind rewrites the ? placeholders of query into the dialect's numbered
+// style after offset, leaving quoted literals untouched; ?? is kept as a
+// literal ? for operators such as postgres jsonb and ?NNN is left alone.
+// Queries for dialects that use ? themselves are returned unchanged.
+func rebind(dialect Dialect, query string, offset int) string {
+	if dialect.Placeholder(1) == "?" {
+		return query
+	}
+	var sb strings.Builder
+	var count = 0
+	var quote rune = 0
+	var runes = []rune(query)
+	for i := 0; i < len(runes); i++ {
+		var ch = runes[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '?' && i+1 < len(runes) && runes[i+1] == '?':
+			i++
+		case ch == '?' && i+1 < len(runes) && runes[i+1] >= '0' && runes[i+1] <= '9':
+			// explicitly numbered ?NNN parameter, left to the driver
+		case ch == '?':
+			count++
+			sb.WriteString(dialect.Placeholder(offset + count))
+			continue
+		}
+		sb.WriteRune(ch)
+	}
+	return sb.String()
+}
+
+func returningClause(dialect Dialect, returning []string) string {
+	if len(returning) == 0 {
+		return ""
+	}
+	return " RETURNING " + QuotedColumnNames(returning, dialect)
+}
+
+func checkReturning(dialect Dialect) error {
+	if !dialect.SupportsReturning() {
+		return fmt.Errorf("%w by %v", ErrReturningNotSupported, dialect.Name())
+	}
+	return nil
+}
+
 func checkError(err error) {
 	if err != nil {
 		panic(err)
@@ -0,0 +1,143 @@
+package dblite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type SchemaDiff struct {
+	Table           string
+	MissingTable    bool
+	MissingColumns  []string // model fields without a column
+	RequiredColumns []string // unmapped NOT NULL columns without a default, inserts will fail
+	TypeMismatches  []TypeMismatch
+}
+
+type TypeMismatch struct {
+	Column   string
+	Expected string // column type of the model field on this dialect
+	Actual   string // declared column type
+}
+
+func (d SchemaDiff) String() string {
+	if d.MissingTable {
+		return fmt.Sprintf("table %v: missing", d.Table)
+	}
+	var parts = make([]string, 0, 3)
+	if len(d.MissingColumns) > 0 {
+		parts = append(parts, "missing columns "+strings.Join(d.MissingColumns, ", "))
+	}
+	if len(d.RequiredColumns) > 0 {
+		parts = append(parts, "unmapped required columns "+strings.Join(d.RequiredColumns, ", "))
+	}
+	for _, m := range d.TypeMismatches {
+		parts = append(parts, fmt.Sprintf("column %v is %v, expected %v", m.Column, m.Actual, m.Expected))
+	}
+	return fmt.Sprintf("table %v: %v", d.Table, strings.Join(parts, "; "))
+}
+
+// VerifySchema compares the models' fields against their live tables and
+// returns a diff for every table that drifted, or none if all match.
+func VerifySchema(ctx context.Context, db DB, models ...interface{ TableName() string }) ([]SchemaDiff, error) {
+	var diffs = make([]SchemaDiff, 0)
+	for _, model := range models {
+		var diff, err = verifyTable(ctx, db, model)
+		if err != nil {
+			return nil, err
+		}
+		if diff.MissingTable || len(diff.MissingColumns) > 0 || len(diff.RequiredColumns) > 0 || len(diff.TypeMismatches) > 0 {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs, nil
+}
+
+func verifyTable(ctx context.Context, db DB, model interface{ TableName() string }) (SchemaDiff, error) {
+	var diff = SchemaDiff{Table: model.TableName()}
+	var fields, err = ModelFields(model)
+	if err != nil {
+		return diff, err
+	}
+	columns, err := Columns(ctx, db, diff.Table)
+	if errors.Is(err, ErrNotFound) {
+		diff.MissingTable = true
+		return diff, nil
+	}
+	if err != nil {
+		return diff, err
+	}
+
+	var byName = make(map[string]ColumnInfo, len(columns))
+	for _, c := range columns {
+		byName[c.Name] = c
+	}
+	var mapped = make(map[string]bool, len(fields))
+	for _, f := range fields {
+		mapped[f.Name] = true
+		var c, ok = byName[f.Name]
+		if !ok { // an unquoted mixed case name is stored folded by postgres
+			c, ok = byName[strings.ToLower(f.Name)]
+			mapped[c.Name] = ok
+		}
+		if !ok {
+			diff.MissingColumns = append(diff.MissingColumns, f.Name)
+			continue
+		}
+		var expected = f.SQLType
+		if expected == "" {
+			expected = db.Dialect().ColumnType(f)
+		}
+		if expected != "" && !compatibleTypes(typeFamily(expected), typeFamily(c.Type)) {
+			diff.TypeMismatches = append(diff.TypeMismatches, TypeMismatch{Column: f.Name, Expected: expected, Actual: c.Type})
+		}
+	}
+	for _, c := range columns {
+		if !mapped[c.Name] && c.NotNull && !c.Default.Valid {
+			diff.RequiredColumns = append(diff.RequiredColumns, c.Name)
+		}
+	}
+	return diff, nil
+}
+
+// typeFamily groups SQL types by the Go values they scan into, following
+// sqlite's affinity rules for names it does not know.
+func typeFamily(sqlType string) string {
+	var t = strings.ToUpper(sqlType)
+	switch {
+	case t == "":
+		return "" // untyped sqlite column, accepts anything
+	case strings.Contains(t, "INT"):
+		return "integer"
+	case strings.HasPrefix(t, "BOOL"):
+		return "bool"
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "TEXT"), strings.Contains(t, "CLOB"),
+		strings.HasPrefix(t, "UUID"), strings.HasPrefix(t, "JSON"):
+		return "text"
+	case strings.Contains(t, "BLOB"), strings.Contains(t, "BINARY"), t == "BYTEA":
+		return "blob"
+	case strings.HasPrefix(t, "TIME"), strings.HasPrefix(t, "DATE"):
+		return "time"
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"),
+		strings.HasPrefix(t, "NUMERIC"), strings.HasPrefix(t, "DECIMAL"):
+		return "real"
+	}
+	return t
+}
+
+// compatibleTypes reports whether a column of the actual family scans into a
+// field expecting the expected family.
+func compatibleTypes(expected, actual string) bool {
+	switch {
+	case expected == actual, expected == "", actual == "":
+		return true
+	case expected == "bool": // sqlite and mysql store booleans as integers
+		return actual == "integer"
+	case expected == "real":
+		return actual == "integer"
+	case expected == "text": // times scan into strings
+		return actual == "time"
+	}
+	return false
+}
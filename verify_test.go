@@ -0,0 +1,61 @@
+package dblite
+
+import (
+	"context"
+	"github.com/franela/goblin"
+	"testing"
+	"time"
+)
+
+type DriftedModel struct {
+	Id     int64  `db:"id,pk"`
+	Name   int64  `db:"name"`
+	Phone  string `db:"phone"`
+	Active bool   `db:"active"`
+}
+
+func (m *DriftedModel) TableName() string {
+	return "model"
+}
+
+type MissingModel struct {
+	Id int64 `db:"id,pk"`
+}
+
+func (m *MissingModel) TableName() string {
+	return "missing_model"
+}
+
+func TestVerifySchema(t *testing.T) {
+	g := goblin.Goblin(t)
+	g.Describe("Test VerifySchema", func() {
+		g.It("reports schema drift", func() {
+			g.Timeout(1 * time.Hour)
+			initAccountDB()
+			defer deInitDB()
+
+			var ctx = context.Background()
+			g.Assert(CreateTable(ctx, dbInstance, &Event{})).IsNil()
+			diffs, err := VerifySchema(ctx, dbInstance, &Model{}, &Account{}, &Event{})
+			g.Assert(err).IsNil()
+			g.Assert(len(diffs)).Equal(0)
+
+			diffs, err = VerifySchema(ctx, dbInstance, &DriftedModel{}, &MissingModel{})
+			g.Assert(err).IsNil()
+			g.Assert(diffs).Equal([]SchemaDiff{
+				{
+					Table:           "model",
+					MissingColumns:  []string{"phone"},
+					RequiredColumns: []string{"email"},
+					TypeMismatches:  []TypeMismatch{{Column: "name", Expected: "INTEGER", Actual: "TEXT"}},
+				},
+				{Table: "missing_model", MissingTable: true},
+			})
+			g.Assert(diffs[0].String()).Equal("table model: missing columns phone; unmapped required columns email; column name is TEXT, expected INTEGER")
+			g.Assert(diffs[1].String()).Equal("table missing_model: missing")
+
+			_, err = VerifySchema(ctx, Bind(dbInstance.Conn, MySQL), &Model{})
+			g.Assert(err == nil).IsFalse()
+		})
+	})
+}